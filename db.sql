@@ -11,6 +11,14 @@ CREATE TABLE IF NOT EXISTS users(
     role TEXT
 );
 
+-- Skipped while emails differing only in case exist, migrations/008_users_email_lower.sql reports them.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM users WHERE email IS NOT NULL GROUP BY LOWER(email) HAVING COUNT(*) > 1) THEN
+        CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower ON users (LOWER(email));
+    END IF;
+END $$;
+
 CREATE TABLE IF NOT EXISTS students(
     id TEXT PRIMARY KEY REFERENCES users(id),
     school_year TEXT,
@@ -30,38 +38,156 @@ CREATE TABLE IF NOT EXISTS subjects (
     major TEXT
 );
 
+CREATE TABLE IF NOT EXISTS subject_prerequisites (
+    subject_id TEXT REFERENCES subjects(id) ON DELETE CASCADE,
+    prerequisite_id TEXT REFERENCES subjects(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    PRIMARY KEY (subject_id, prerequisite_id),
+    CHECK (subject_id <> prerequisite_id)
+);
+
+CREATE TABLE IF NOT EXISTS terms (
+    code TEXT PRIMARY KEY,
+    academic_year TEXT NOT NULL,
+    semester_number INT NOT NULL CHECK (semester_number BETWEEN 1 AND 3),
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    registration_open TEXT NOT NULL,
+    registration_close TEXT NOT NULL,
+    add_drop_deadline TEXT NOT NULL,
+    is_current BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (academic_year, semester_number)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS terms_single_current ON terms (is_current) WHERE is_current;
+
 CREATE TABLE IF NOT EXISTS courses (
     id TEXT PRIMARY KEY,
     teacher_id TEXT REFERENCES teachers(id) ON DELETE CASCADE,
     subject_id TEXT REFERENCES subjects(id) ON DELETE CASCADE,
-    semester_number INT NOT NULL,
-    academic_year TEXT NOT NULL,
+    term_code TEXT NOT NULL REFERENCES terms(code),
     status TEXT,
     capacity INT,
-    size INT
+    size INT,
+    waitlist_enabled BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id TEXT PRIMARY KEY,
+    building TEXT NOT NULL,
+    capacity INT NOT NULL CHECK (capacity > 0),
+    features TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS course_status_history (
+    id SERIAL PRIMARY KEY,
+    course_id TEXT REFERENCES courses(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at BIGINT NOT NULL
 );
 
 CREATE TABLE IF NOT EXISTS course_schedules (
     id SERIAL PRIMARY KEY,
     course_id TEXT REFERENCES courses(id) ON DELETE CASCADE,
-    room TEXT,
-    start_time INT,
-    end_time INT
+    room TEXT REFERENCES rooms(id),
+    day_of_week INT NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    timezone TEXT NOT NULL,
+    exception_dates TEXT[] NOT NULL DEFAULT '{}'
 );
 
 CREATE TABLE IF NOT EXISTS course_registrations (
     id SERIAL PRIMARY KEY,
     course_id TEXT REFERENCES courses(id) ON DELETE CASCADE,
     student_id TEXT REFERENCES students(id) ON DELETE CASCADE,
+    final_score DOUBLE PRECISION,
+    letter_grade TEXT,
+    grade_point DOUBLE PRECISION,
+    grade_finalized BOOLEAN NOT NULL DEFAULT FALSE,
     UNIQUE (course_id,student_id)
 );
 
+CREATE TABLE IF NOT EXISTS course_waitlists (
+    id SERIAL PRIMARY KEY,
+    course_id TEXT REFERENCES courses(id) ON DELETE CASCADE,
+    student_id TEXT REFERENCES students(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    status TEXT NOT NULL,
+    offer_expires_at BIGINT,
+    UNIQUE (course_id, student_id)
+);
+
 CREATE TABLE IF NOT EXISTS component_scores(
     id SERIAL PRIMARY KEY,
     course_id TEXT REFERENCES courses(id) ON DELETE CASCADE,
-    name TEXT,
-    score_weight DOUBLE PRECISION,
-    score DOUBLE PRECISION
+    name TEXT NOT NULL,
+    score_weight DOUBLE PRECISION NOT NULL CHECK (score_weight > 0 AND score_weight <= 100),
+    UNIQUE (course_id, name) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE TABLE IF NOT EXISTS student_scores(
+    id SERIAL PRIMARY KEY,
+    component_id INT REFERENCES component_scores(id) ON DELETE CASCADE,
+    student_id TEXT REFERENCES students(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL CHECK (score >= 0 AND score <= 10),
+    UNIQUE (component_id, student_id)
+);
+
+CREATE TABLE IF NOT EXISTS calendar_tokens(
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS auth_events(
+    id SERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    event TEXT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS auth_events_user_id ON auth_events (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS user_totp(
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes(
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_policies(
+    role TEXT PRIMARY KEY,
+    required BOOLEAN NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS jwt_signing_keys(
+    kid TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    activates_at BIGINT NOT NULL,
+    expires_at BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS user_identities(
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (issuer, subject)
 );
 
 INSERT INTO users (
@@ -77,7 +203,7 @@ INSERT INTO users (
              '123 Admin Street, Admin City',
              '$2a$04$PFxZjH2Jwb2aL0yslR8N.eo8Cw.PEcdFjB68a7d3tBmImq3yAtR1S',
              'Admin'
-         );
+         ) ON CONFLICT (id) DO NOTHING;
 
 
 
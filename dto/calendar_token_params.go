@@ -0,0 +1,7 @@
+package dto
+
+type CalendarTokenParams struct {
+	UserId string `json:"user_id" validate:"required"`
+	// BaseUrl is where the API is reached from, used to build the feed URL.
+	BaseUrl string `json:"-"`
+}
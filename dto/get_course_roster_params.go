@@ -0,0 +1,7 @@
+package dto
+
+type GetCourseRosterParams struct {
+	CourseId string `json:"course_id" validate:"required"`
+	// Format is "json" by default, "csv" downloads the roster as an attendance sheet.
+	Format string `json:"format" validate:"omitempty,oneof=json csv"`
+}
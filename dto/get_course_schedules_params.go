@@ -0,0 +1,8 @@
+package dto
+
+type GetCourseSchedulesParams struct {
+	CourseId string `json:"course_id" validate:"required"`
+	Expand   bool   `json:"expand"`
+	From     string `json:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `json:"to" validate:"omitempty,datetime=2006-01-02"`
+}
@@ -1,7 +1,8 @@
 package dto
 
 type GetCoursesParams struct {
-	UserId       string `json:"user_id" validate:"required"`
-	Semester     int    `json:"semester" validate:"required"`
-	AcademicYear string `json:"academic_year" validate:"required"`
+	PaginationParams
+	UserId string `json:"user_id" validate:"required"`
+	// TermCode defaults to the current term when empty.
+	TermCode string `json:"term_code"`
 }
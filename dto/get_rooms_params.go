@@ -0,0 +1,15 @@
+package dto
+
+type GetRoomsParams struct {
+	PaginationParams
+	Building    string   `json:"building"`
+	MinCapacity int      `json:"min_capacity"`
+	Features    []string `json:"features"`
+}
+
+type FindFreeRoomsParams struct {
+	From        string   `json:"from" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	To          string   `json:"to" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	MinCapacity int      `json:"min_capacity"`
+	Features    []string `json:"features"`
+}
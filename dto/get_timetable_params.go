@@ -0,0 +1,7 @@
+package dto
+
+type GetTimetableParams struct {
+	UserId string `json:"user_id" validate:"required"`
+	// Week is any date of the wanted week, the current week when empty.
+	Week string `json:"week" validate:"omitempty,datetime=2006-01-02"`
+}
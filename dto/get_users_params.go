@@ -0,0 +1,20 @@
+package dto
+
+type GetStudentsParams struct {
+	PaginationParams
+	Major      string `json:"major"`
+	SchoolYear string `json:"school_year"`
+	Gender     string `json:"gender"`
+	Name       string `json:"name"`
+	SortBy     string `json:"sort_by" validate:"omitempty,oneof=id name date_of_birth school_year major"`
+	SortOrder  string `json:"sort_order" validate:"omitempty,oneof=asc desc"`
+}
+
+type GetTeachersParams struct {
+	PaginationParams
+	Department string `json:"department"`
+	Gender     string `json:"gender"`
+	Name       string `json:"name"`
+	SortBy     string `json:"sort_by" validate:"omitempty,oneof=id name date_of_birth department"`
+	SortOrder  string `json:"sort_order" validate:"omitempty,oneof=asc desc"`
+}
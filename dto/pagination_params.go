@@ -3,4 +3,23 @@ package dto
 type PaginationParams struct {
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
+	// Cursor is an opaque cursor taken from a previous page. Lists paged by cursor ignore Offset.
+	Cursor       string `json:"cursor"`
+	IncludeTotal bool   `json:"include_total"`
+}
+
+// Cursor is the decoded form of PaginationParams.Cursor: the sort key of the row next to the wanted page.
+type Cursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o,omitempty"`
+	Value     string `json:"v"`
+	Id        string `json:"id"`
+	Backward  bool   `json:"b,omitempty"`
+}
+
+type PageInfo struct {
+	NextCursor string
+	PrevCursor string
+	// Total is only set when PaginationParams.IncludeTotal was requested.
+	Total *int
 }
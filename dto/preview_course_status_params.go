@@ -0,0 +1,6 @@
+package dto
+
+type PreviewCourseStatusParams struct {
+	// Date defaults to today.
+	Date string `json:"date" validate:"omitempty,datetime=2006-01-02"`
+}
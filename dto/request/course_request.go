@@ -3,24 +3,27 @@ package request
 import "SchoolManagement/model"
 
 type CourseRequest struct {
-	Id             string `json:"id" validate:"required"`
-	TeacherId      string `json:"teacher_id" validate:"required"`
-	SubjectId      string `json:"subject_id" validate:"required"`
-	SemesterNumber int    `json:"semester_number" validate:"required"`
-	AcademicYear   string `json:"academic_year" validate:"required"`
-	Capacity       int    `json:"capacity" validate:"required"`
-	Status         string `json:"status"`
+	Id        string `json:"id" validate:"required"`
+	TeacherId string `json:"teacher_id" validate:"required"`
+	SubjectId string `json:"subject_id" validate:"required"`
+	TermCode  string `json:"term_code" validate:"required"`
+	Capacity  int    `json:"capacity" validate:"required"`
+	Status    string `json:"status"`
+	// WaitlistEnabled lets students queue for a seat once the course is full.
+	WaitlistEnabled *bool `json:"waitlist_enabled"`
+	// StatusReason is recorded in the course history when Status changes.
+	StatusReason string `json:"status_reason"`
 }
 
 func (req *CourseRequest) ToCourse() model.Course {
 	return model.Course{
-		Id:             req.Id,
-		TeacherId:      req.TeacherId,
-		SubjectId:      req.SubjectId,
-		SemesterNumber: req.SemesterNumber,
-		AcademicYear:   req.AcademicYear,
-		Capacity:       req.Capacity,
-		Status:         req.Status,
-		Size:           0,
+		Id:              req.Id,
+		TeacherId:       req.TeacherId,
+		SubjectId:       req.SubjectId,
+		TermCode:        req.TermCode,
+		Capacity:        req.Capacity,
+		Status:          req.Status,
+		Size:            0,
+		WaitlistEnabled: req.WaitlistEnabled,
 	}
 }
@@ -1,19 +1,32 @@
 package request
 
-import "SchoolManagement/model"
+import (
+	"SchoolManagement/model"
+	"github.com/lib/pq"
+)
 
 type CourseScheduleRequest struct {
-	CourseId  string `json:"course_id" validate:"required"`
-	Room      string `json:"room" validate:"required"`
-	StartTime int64  `json:"start_time" validate:"required"`
-	EndTime   int64  `json:"end_time" validate:"required"`
+	CourseId       string   `json:"course_id" validate:"required"`
+	Room           string   `json:"room" validate:"required"`
+	DayOfWeek      int      `json:"day_of_week" validate:"required,min=1,max=7"`
+	StartTime      string   `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime        string   `json:"end_time" validate:"required,datetime=15:04"`
+	StartDate      string   `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate        string   `json:"end_date" validate:"required,datetime=2006-01-02"`
+	Timezone       string   `json:"timezone" validate:"required,timezone"`
+	ExceptionDates []string `json:"exception_dates" validate:"dive,datetime=2006-01-02"`
 }
 
 func (req *CourseScheduleRequest) ToCourseSchedule() model.CourseSchedule {
 	return model.CourseSchedule{
-		CourseId:  req.CourseId,
-		Room:      req.Room,
-		StartTime: req.StartTime,
-		EndTime:   req.EndTime,
+		CourseId:       req.CourseId,
+		Room:           req.Room,
+		DayOfWeek:      req.DayOfWeek,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		Timezone:       req.Timezone,
+		ExceptionDates: append(pq.StringArray{}, req.ExceptionDates...),
 	}
 }
@@ -0,0 +1,5 @@
+package request
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
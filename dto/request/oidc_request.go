@@ -0,0 +1,12 @@
+package request
+
+// OidcCallbackRequest is what the provider redirects back with. Error is set instead of the code when the login was
+// denied or failed at the provider.
+type OidcCallbackRequest struct {
+	Code             string `validate:"required_without=Error"`
+	State            string `validate:"required"`
+	Error            string
+	ErrorDescription string
+	// BrowserBinding is the cookie set when the login was started.
+	BrowserBinding string
+}
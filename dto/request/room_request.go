@@ -0,0 +1,25 @@
+package request
+
+import (
+	"SchoolManagement/model"
+	"github.com/lib/pq"
+)
+
+type RoomRequest struct {
+	Id       string   `json:"id" validate:"required"`
+	Building string   `json:"building" validate:"required"`
+	Capacity int      `json:"capacity" validate:"required,gt=0"`
+	Features []string `json:"features" validate:"dive,oneof=projector lab"`
+}
+
+func (req *RoomRequest) ToRoom() model.Room {
+	room := model.Room{
+		Id:       req.Id,
+		Building: req.Building,
+		Capacity: req.Capacity,
+	}
+	if req.Features != nil {
+		room.Features = append(pq.StringArray{}, req.Features...)
+	}
+	return room
+}
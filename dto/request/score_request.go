@@ -0,0 +1,50 @@
+package request
+
+import "SchoolManagement/model"
+
+type ComponentScoreRequest struct {
+	Id          int     `json:"id"`
+	Name        string  `json:"name" validate:"required"`
+	ScoreWeight float64 `json:"score_weight" validate:"required,gt=0,lte=100"`
+}
+
+type ComponentScoresRequest struct {
+	CourseId   string                  `json:"course_id" validate:"required"`
+	Components []ComponentScoreRequest `json:"components" validate:"required,min=1,dive"`
+}
+
+func (req *ComponentScoresRequest) ToComponentScores() []model.ComponentScore {
+	var components []model.ComponentScore
+	for _, component := range req.Components {
+		components = append(components, model.ComponentScore{
+			Id:          component.Id,
+			CourseId:    req.CourseId,
+			Name:        component.Name,
+			ScoreWeight: component.ScoreWeight,
+		})
+	}
+	return components
+}
+
+type StudentScoreRequest struct {
+	StudentId   string  `json:"student_id" validate:"required"`
+	ComponentId int     `json:"component_id" validate:"required"`
+	Score       float64 `json:"score" validate:"gte=0,lte=10"`
+}
+
+type StudentScoresRequest struct {
+	CourseId string                `json:"course_id" validate:"required"`
+	Scores   []StudentScoreRequest `json:"scores" validate:"required,min=1,dive"`
+}
+
+func (req *StudentScoresRequest) ToStudentScores() []model.StudentScore {
+	var scores []model.StudentScore
+	for _, score := range req.Scores {
+		scores = append(scores, model.StudentScore{
+			ComponentId: score.ComponentId,
+			StudentId:   score.StudentId,
+			Score:       score.Score,
+		})
+	}
+	return scores
+}
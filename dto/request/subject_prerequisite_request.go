@@ -0,0 +1,17 @@
+package request
+
+import "SchoolManagement/model"
+
+type SubjectPrerequisiteRequest struct {
+	SubjectId      string `json:"subject_id" validate:"required"`
+	PrerequisiteId string `json:"prerequisite_id" validate:"required"`
+	Type           string `json:"type" validate:"required,oneof=Prerequisite Corequisite"`
+}
+
+func (req *SubjectPrerequisiteRequest) ToSubjectPrerequisite() model.SubjectPrerequisite {
+	return model.SubjectPrerequisite{
+		SubjectId:      req.SubjectId,
+		PrerequisiteId: req.PrerequisiteId,
+		Type:           req.Type,
+	}
+}
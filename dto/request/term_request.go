@@ -0,0 +1,29 @@
+package request
+
+import "SchoolManagement/model"
+
+type TermRequest struct {
+	Code              string `json:"code" validate:"required"`
+	AcademicYear      string `json:"academic_year" validate:"required"`
+	SemesterNumber    int    `json:"semester_number" validate:"required,gte=1,lte=3"`
+	StartDate         string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate           string `json:"end_date" validate:"required,datetime=2006-01-02"`
+	RegistrationOpen  string `json:"registration_open" validate:"required,datetime=2006-01-02"`
+	RegistrationClose string `json:"registration_close" validate:"required,datetime=2006-01-02"`
+	AddDropDeadline   string `json:"add_drop_deadline" validate:"required,datetime=2006-01-02"`
+	IsCurrent         *bool  `json:"is_current"`
+}
+
+func (req *TermRequest) ToTerm() model.Term {
+	return model.Term{
+		Code:              req.Code,
+		AcademicYear:      req.AcademicYear,
+		SemesterNumber:    req.SemesterNumber,
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+		RegistrationOpen:  req.RegistrationOpen,
+		RegistrationClose: req.RegistrationClose,
+		AddDropDeadline:   req.AddDropDeadline,
+		IsCurrent:         req.IsCurrent,
+	}
+}
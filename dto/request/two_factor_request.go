@@ -0,0 +1,19 @@
+package request
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorPolicyRequest struct {
+	Role     string `json:"-" validate:"oneof=Admin Teacher"`
+	Required *bool  `json:"required" validate:"required"`
+}
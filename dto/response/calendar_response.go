@@ -0,0 +1,12 @@
+package response
+
+type CalendarTokenResponse struct {
+	Token   string `json:"token"`
+	FeedUrl string `json:"feed_url"`
+}
+
+// IcsFile is written as a text/calendar document instead of JSON.
+type IcsFile struct {
+	FileName string
+	Content  string
+}
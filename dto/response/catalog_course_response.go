@@ -0,0 +1,26 @@
+package response
+
+type CatalogCourseResponse struct {
+	Id              string                    `json:"id"`
+	SubjectId       string                    `json:"subject_id"`
+	SubjectName     string                    `json:"subject_name"`
+	NumberOfCredit  int                       `json:"number_of_credit"`
+	Major           string                    `json:"major"`
+	TeacherId       string                    `json:"teacher_id"`
+	TeacherName     string                    `json:"teacher_name"`
+	TermCode        string                    `json:"term_code"`
+	Status          string                    `json:"status"`
+	Capacity        int                       `json:"capacity"`
+	Size            int                       `json:"size"`
+	RemainingSeats  int                       `json:"remaining_seats"`
+	WaitlistEnabled bool                      `json:"waitlist_enabled"`
+	Schedules       []ScheduleSummaryResponse `json:"schedules"`
+}
+
+type ScheduleSummaryResponse struct {
+	DayOfWeek int    `json:"day_of_week"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Room      string `json:"room"`
+	Timezone  string `json:"timezone"`
+}
@@ -1,9 +1,22 @@
 package response
 
 type CourseScheduleResponse struct {
-	Id        int    `json:"id"`
-	CourseId  string `json:"course_id"`
-	Room      string `json:"room"`
-	StartTime int64  `json:"start_time"`
-	EndTime   int64  `json:"end_time"`
+	Id             int      `json:"id"`
+	CourseId       string   `json:"course_id"`
+	Room           string   `json:"room"`
+	DayOfWeek      int      `json:"day_of_week"`
+	StartTime      string   `json:"start_time"`
+	EndTime        string   `json:"end_time"`
+	StartDate      string   `json:"start_date"`
+	EndDate        string   `json:"end_date"`
+	Timezone       string   `json:"timezone"`
+	ExceptionDates []string `json:"exception_dates"`
+}
+
+type CourseSessionResponse struct {
+	ScheduleId int    `json:"schedule_id"`
+	CourseId   string `json:"course_id"`
+	Room       string `json:"room"`
+	StartTime  int64  `json:"start_time"`
+	EndTime    int64  `json:"end_time"`
 }
@@ -0,0 +1,12 @@
+package response
+
+type CourseStatusChangeResponse struct {
+	CourseId   string `json:"course_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Actor      string `json:"actor"`
+	Reason     string `json:"reason"`
+	CreatedAt  int64  `json:"created_at"`
+	// BlockedReason is only set in previews, for changes the scheduler would not be allowed to make.
+	BlockedReason string `json:"blocked_reason,omitempty"`
+}
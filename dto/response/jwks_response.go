@@ -0,0 +1,18 @@
+package response
+
+// Jwk is a public key in the JSON Web Key format of RFC 7517.
+type Jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JwksResponse struct {
+	Keys []Jwk `json:"keys"`
+}
@@ -1,7 +1,16 @@
 package response
 
+// LoginResponse carries the tokens of a completed login. When a second factor is needed it carries the challenge
+// token to complete the login with instead.
 type LoginResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int64  `json:"expires_in"`
-	Role        string `json:"role"`
+	AccessToken            string   `json:"access_token,omitempty"`
+	ExpiresIn              int64    `json:"expires_in,omitempty"`
+	RefreshToken           string   `json:"refresh_token,omitempty"`
+	RefreshExpiresIn       int64    `json:"refresh_expires_in,omitempty"`
+	Role                   string   `json:"role,omitempty"`
+	TwoFactorRequired      bool     `json:"two_factor_required"`
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"`
+	ChallengeToken         string   `json:"challenge_token,omitempty"`
+	ChallengeExpiresIn     int64    `json:"challenge_expires_in,omitempty"`
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`
 }
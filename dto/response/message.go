@@ -4,3 +4,8 @@ type Message struct {
 	Message string `json:"message,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
+
+type ScheduleConflictMessage struct {
+	Error       string `json:"error"`
+	ScheduleIds []int  `json:"schedule_ids"`
+}
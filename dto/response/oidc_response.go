@@ -0,0 +1,9 @@
+package response
+
+type OidcLoginResponse struct {
+	AuthorizationUrl string `json:"authorization_url"`
+	// BrowserBinding goes into an HttpOnly cookie that the callback has to come back with. It is Secure when the
+	// callback is served over https.
+	BrowserBinding string `json:"-"`
+	SecureCookie   bool   `json:"-"`
+}
@@ -0,0 +1,23 @@
+package response
+
+import "SchoolManagement/dto"
+
+// Page is the envelope shared by the cursor paged list endpoints.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+}
+
+func NewPage[T any](items []T, info dto.PageInfo) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{
+		Items:      items,
+		NextCursor: info.NextCursor,
+		PrevCursor: info.PrevCursor,
+		Total:      info.Total,
+	}
+}
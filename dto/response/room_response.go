@@ -0,0 +1,8 @@
+package response
+
+type RoomResponse struct {
+	Id       string   `json:"id"`
+	Building string   `json:"building"`
+	Capacity int      `json:"capacity"`
+	Features []string `json:"features"`
+}
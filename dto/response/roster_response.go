@@ -0,0 +1,16 @@
+package response
+
+type RosterStudentResponse struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Major      string `json:"major"`
+	SchoolYear string `json:"school_year"`
+}
+
+// CsvFile is written as a CSV attachment instead of JSON.
+type CsvFile struct {
+	FileName string
+	Header   []string
+	Rows     [][]string
+}
@@ -0,0 +1,29 @@
+package response
+
+type ComponentScoreResponse struct {
+	Id          int     `json:"id"`
+	Name        string  `json:"name"`
+	ScoreWeight float64 `json:"score_weight"`
+}
+
+type ComponentScoreValue struct {
+	ComponentId   int     `json:"component_id"`
+	ComponentName string  `json:"component_name"`
+	Score         float64 `json:"score"`
+}
+
+type StudentScoresResponse struct {
+	StudentId string                `json:"student_id"`
+	Scores    []ComponentScoreValue `json:"scores"`
+}
+
+type FinalGradeResponse struct {
+	StudentId      string  `json:"student_id"`
+	FinalScore     float64 `json:"final_score"`
+	LetterGrade    string  `json:"letter_grade"`
+	GradePoint     float64 `json:"grade_point"`
+	GradeFinalized bool    `json:"grade_finalized"`
+	// MissingComponents is set on previewed grades of students that are not fully graded yet, which block
+	// finalizing.
+	MissingComponents []string `json:"missing_components,omitempty"`
+}
@@ -0,0 +1,7 @@
+package response
+
+type SubjectPrerequisiteResponse struct {
+	PrerequisiteId   string `json:"prerequisite_id"`
+	PrerequisiteName string `json:"prerequisite_name"`
+	Type             string `json:"type"`
+}
@@ -0,0 +1,13 @@
+package response
+
+type TermResponse struct {
+	Code              string `json:"code"`
+	AcademicYear      string `json:"academic_year"`
+	SemesterNumber    int    `json:"semester_number"`
+	StartDate         string `json:"start_date"`
+	EndDate           string `json:"end_date"`
+	RegistrationOpen  string `json:"registration_open"`
+	RegistrationClose string `json:"registration_close"`
+	AddDropDeadline   string `json:"add_drop_deadline"`
+	IsCurrent         bool   `json:"is_current"`
+}
@@ -0,0 +1,23 @@
+package response
+
+type TimetableEntryResponse struct {
+	CourseId    string `json:"course_id"`
+	SubjectName string `json:"subject_name"`
+	TeacherName string `json:"teacher_name"`
+	Room        string `json:"room"`
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	Timezone    string `json:"timezone"`
+}
+
+type TimetableDayResponse struct {
+	Date      string                   `json:"date"`
+	DayOfWeek int                      `json:"day_of_week"`
+	Entries   []TimetableEntryResponse `json:"entries"`
+}
+
+type TimetableResponse struct {
+	UserId    string                 `json:"user_id"`
+	WeekStart string                 `json:"week_start"`
+	Days      []TimetableDayResponse `json:"days"`
+}
@@ -0,0 +1,27 @@
+package response
+
+type TranscriptCourseResponse struct {
+	CourseId       string  `json:"course_id"`
+	SubjectId      string  `json:"subject_id"`
+	SubjectName    string  `json:"subject_name"`
+	NumberOfCredit int     `json:"number_of_credit"`
+	FinalScore     float64 `json:"final_score"`
+	LetterGrade    string  `json:"letter_grade"`
+	GradePoint     float64 `json:"grade_point"`
+}
+
+type TranscriptTermResponse struct {
+	TermCode       string                     `json:"term_code"`
+	AcademicYear   string                     `json:"academic_year"`
+	SemesterNumber int                        `json:"semester_number"`
+	Courses        []TranscriptCourseResponse `json:"courses"`
+	Credits        int                        `json:"credits"`
+	TermGpa        float64                    `json:"term_gpa"`
+}
+
+type TranscriptResponse struct {
+	StudentId          string                   `json:"student_id"`
+	Terms              []TranscriptTermResponse `json:"terms"`
+	CumulativeGpa      float64                  `json:"cumulative_gpa"`
+	TotalEarnedCredits int                      `json:"total_earned_credits"`
+}
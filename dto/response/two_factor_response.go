@@ -0,0 +1,15 @@
+package response
+
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorPolicyResponse struct {
+	Role     string `json:"role"`
+	Required bool   `json:"required"`
+}
@@ -0,0 +1,9 @@
+package response
+
+type WaitlistEntryResponse struct {
+	StudentId      string `json:"student_id"`
+	StudentName    string `json:"student_name"`
+	Position       int    `json:"position"`
+	Status         string `json:"status"`
+	OfferExpiresAt int64  `json:"offer_expires_at,omitempty"`
+}
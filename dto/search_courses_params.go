@@ -0,0 +1,16 @@
+package dto
+
+type SearchCoursesParams struct {
+	PaginationParams
+	TermCode     string `json:"term_code"`
+	SubjectId    string `json:"subject_id"`
+	Major        string `json:"major"`
+	TeacherId    string `json:"teacher_id"`
+	Status       string `json:"status" validate:"omitempty,oneof=Initial Register Ongoing Complete"`
+	HasFreeSeats bool   `json:"has_free_seats"`
+	Credits      int    `json:"credits" validate:"min=0"`
+	// DayOfWeek, From and To keep the courses having a weekly session inside that window.
+	DayOfWeek int    `json:"day_of_week" validate:"min=0,max=7"`
+	From      string `json:"from" validate:"omitempty,datetime=15:04"`
+	To        string `json:"to" validate:"omitempty,datetime=15:04"`
+}
@@ -2,6 +2,8 @@ package endpoint
 
 import (
 	request2 "SchoolManagement/dto/request"
+	"SchoolManagement/dto/response"
+	error2 "SchoolManagement/error"
 	"SchoolManagement/service"
 	"context"
 	"github.com/go-kit/kit/endpoint"
@@ -10,6 +12,14 @@ import (
 
 type AuthEndpoint interface {
 	Login() endpoint.Endpoint
+	Refresh() endpoint.Endpoint
+	Logout() endpoint.Endpoint
+	RevokeUserSessions() endpoint.Endpoint
+	UnlockAccount() endpoint.Endpoint
+	LoginTwoFactor() endpoint.Endpoint
+	EnrollTwoFactorWithChallenge() endpoint.Endpoint
+	StartOidcLogin() endpoint.Endpoint
+	CompleteOidcLogin() endpoint.Endpoint
 }
 
 type authEndpoint struct {
@@ -31,6 +41,112 @@ func (a *authEndpoint) Login() endpoint.Endpoint {
 	}
 }
 
+func (a *authEndpoint) Refresh() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.RefreshTokenRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		res, err := a.authService.Refresh(ctx, req.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func (a *authEndpoint) Logout() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.LogoutRequest)
+		err := a.authService.Logout(ctx, req.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Logout successfully"}, nil
+	}
+}
+
+func (a *authEndpoint) RevokeUserSessions() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := a.authService.RevokeUserSessions(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Sessions revoked successfully"}, nil
+	}
+}
+
+func (a *authEndpoint) UnlockAccount() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := a.authService.UnlockAccount(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Account unlocked successfully"}, nil
+	}
+}
+
+func (a *authEndpoint) LoginTwoFactor() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TwoFactorLoginRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		res, err := a.authService.LoginTwoFactor(ctx, req.ChallengeToken, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func (a *authEndpoint) EnrollTwoFactorWithChallenge() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TwoFactorChallengeRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		res, err := a.authService.EnrollTwoFactorWithChallenge(ctx, req.ChallengeToken)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func (a *authEndpoint) StartOidcLogin() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		res, err := a.authService.StartOidcLogin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func (a *authEndpoint) CompleteOidcLogin() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.OidcCallbackRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		if req.Error != "" {
+			return nil, &error2.UnauthorizedErr{Message: "Single sign-on failed at the provider: " + req.Error + " " + req.ErrorDescription}
+		}
+		res, err := a.authService.CompleteOidcLogin(ctx, req.Code, req.State, req.BrowserBinding)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
 func NewAuthEndpoint(authService service.AuthService) AuthEndpoint {
 	return &authEndpoint{authService: authService}
 }
@@ -0,0 +1,84 @@
+package endpoint
+
+import (
+	"SchoolManagement/dto"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+)
+
+type CalendarEndpoint interface {
+	CreateCalendarTokenEndpoint() endpoint.Endpoint
+	RevokeCalendarTokenEndpoint() endpoint.Endpoint
+	ExportCalendarEndpoint() endpoint.Endpoint
+	GetCalendarFeedEndpoint() endpoint.Endpoint
+}
+
+type calendarEndpoint struct {
+	calendarService service.CalendarService
+}
+
+func (c *calendarEndpoint) CreateCalendarTokenEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.CalendarTokenParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		token, err := c.calendarService.CreateCalendarToken(ctx, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		return response.CalendarTokenResponse{
+			Token:   token,
+			FeedUrl: req.BaseUrl + "/calendar/feed/" + token,
+		}, nil
+	}
+}
+
+func (c *calendarEndpoint) RevokeCalendarTokenEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.CalendarTokenParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := c.calendarService.RevokeCalendarToken(ctx, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Calendar token revoked"}, nil
+	}
+}
+
+func (c *calendarEndpoint) ExportCalendarEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.CalendarTokenParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		content, err := c.calendarService.ExportCalendar(ctx, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		return response.IcsFile{FileName: req.UserId + "-schedule.ics", Content: content}, nil
+	}
+}
+
+func (c *calendarEndpoint) GetCalendarFeedEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		content, err := c.calendarService.GetCalendarFeed(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.IcsFile{FileName: "schedule.ics", Content: content}, nil
+	}
+}
+
+func NewCalendarEndpoint(calendarService service.CalendarService) CalendarEndpoint {
+	return &calendarEndpoint{calendarService: calendarService}
+}
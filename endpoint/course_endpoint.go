@@ -4,10 +4,13 @@ import (
 	"SchoolManagement/dto"
 	request2 "SchoolManagement/dto/request"
 	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
 	"SchoolManagement/service"
+	"SchoolManagement/utils"
 	"context"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-playground/validator/v10"
+	"time"
 )
 
 type CourseEndpoint interface {
@@ -21,6 +24,13 @@ type CourseEndpoint interface {
 	GetCourseSchedulesByCourseId() endpoint.Endpoint
 	DeleteCourseScheduleById() endpoint.Endpoint
 	GetCoursesByUserId() endpoint.Endpoint
+	JoinWaitlist() endpoint.Endpoint
+	LeaveWaitlist() endpoint.Endpoint
+	AcceptWaitlistOffer() endpoint.Endpoint
+	GetWaitlist() endpoint.Endpoint
+	GetCourseHistory() endpoint.Endpoint
+	SearchCourses() endpoint.Endpoint
+	GetCourseRoster() endpoint.Endpoint
 }
 
 type courseEndpoint struct {
@@ -50,14 +60,16 @@ func (c *courseEndpoint) GetCourseById() endpoint.Endpoint {
 			return nil, err
 		}
 		return response.CourseResponse{
-			Id:             course.Id,
-			TeacherName:    course.TeacherName,
-			SubjectName:    course.SubjectName,
-			SemesterNumber: course.SemesterNumber,
-			AcademicYear:   course.AcademicYear,
-			Capacity:       course.Capacity,
-			Size:           course.Size,
-			Status:         course.Status,
+			Id:              course.Id,
+			TeacherName:     course.TeacherName,
+			SubjectName:     course.SubjectName,
+			TermCode:        course.TermCode,
+			SemesterNumber:  course.SemesterNumber,
+			AcademicYear:    course.AcademicYear,
+			Capacity:        course.Capacity,
+			Size:            course.Size,
+			Status:          course.Status,
+			WaitlistEnabled: course.WaitlistEnabled != nil && *course.WaitlistEnabled,
 		}, nil
 	}
 }
@@ -65,7 +77,7 @@ func (c *courseEndpoint) GetCourseById() endpoint.Endpoint {
 func (c *courseEndpoint) UpdateCourse() endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(request2.CourseRequest)
-		err := c.courseService.UpdateCourse(ctx, req.ToCourse())
+		err := c.courseService.UpdateCourse(ctx, req.ToCourse(), req.StatusReason)
 		if err != nil {
 			return nil, err
 		}
@@ -127,19 +139,54 @@ func (c *courseEndpoint) AddCourseSchedule() endpoint.Endpoint {
 
 func (c *courseEndpoint) GetCourseSchedulesByCourseId() endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(string)
-		schedules, err := c.courseService.GetCourseSchedulesByCourseId(ctx, req)
+		req := request.(dto.GetCourseSchedulesParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		if req.Expand {
+			// from and to are whole UTC days, to is inclusive.
+			var from, to time.Time
+			if req.From != "" {
+				from, _ = time.Parse(utils.DateLayout, req.From)
+			}
+			if req.To != "" {
+				to, _ = time.Parse(utils.DateLayout, req.To)
+				to = to.AddDate(0, 0, 1)
+			}
+			sessions, err := c.courseService.GetCourseSessionsByCourseId(ctx, req.CourseId, from, to)
+			if err != nil {
+				return nil, err
+			}
+			res := []response.CourseSessionResponse{}
+			for _, session := range sessions {
+				res = append(res, response.CourseSessionResponse{
+					ScheduleId: session.ScheduleId,
+					CourseId:   session.CourseId,
+					Room:       session.Room,
+					StartTime:  session.StartTime,
+					EndTime:    session.EndTime,
+				})
+			}
+			return res, nil
+		}
+		schedules, err := c.courseService.GetCourseSchedulesByCourseId(ctx, req.CourseId)
 		if err != nil {
 			return nil, err
 		}
 		var res []response.CourseScheduleResponse
 		for _, schedule := range schedules {
 			res = append(res, response.CourseScheduleResponse{
-				Id:        schedule.Id,
-				CourseId:  schedule.CourseId,
-				Room:      schedule.Room,
-				StartTime: schedule.StartTime,
-				EndTime:   schedule.EndTime,
+				Id:             schedule.Id,
+				CourseId:       schedule.CourseId,
+				Room:           schedule.Room,
+				DayOfWeek:      schedule.DayOfWeek,
+				StartTime:      schedule.StartTime,
+				EndTime:        schedule.EndTime,
+				StartDate:      schedule.StartDate,
+				EndDate:        schedule.EndDate,
+				Timezone:       schedule.Timezone,
+				ExceptionDates: schedule.ExceptionDates,
 			})
 		}
 		return res, nil
@@ -164,21 +211,188 @@ func (c *courseEndpoint) GetCoursesByUserId() endpoint.Endpoint {
 		if err := validate.Struct(req); err != nil {
 			return nil, err
 		}
-		courses, err := c.courseService.GetCoursesByUserId(ctx, req.UserId, req.Semester, req.AcademicYear)
+		if req.Limit <= 0 || req.Limit > 20 {
+			req.Limit = 20
+		}
+		courses, info, err := c.courseService.GetCoursesByUserId(ctx, req)
 		if err != nil {
 			return nil, err
 		}
 		var res []response.CourseResponse
 		for _, course := range courses {
 			res = append(res, response.CourseResponse{
-				Id:             course.Id,
-				TeacherName:    course.TeacherName,
-				SubjectName:    course.SubjectName,
-				SemesterNumber: course.SemesterNumber,
-				AcademicYear:   course.AcademicYear,
-				Capacity:       course.Capacity,
-				Size:           course.Size,
-				Status:         course.Status,
+				Id:              course.Id,
+				TeacherName:     course.TeacherName,
+				SubjectName:     course.SubjectName,
+				TermCode:        course.TermCode,
+				SemesterNumber:  course.SemesterNumber,
+				AcademicYear:    course.AcademicYear,
+				Capacity:        course.Capacity,
+				Size:            course.Size,
+				Status:          course.Status,
+				WaitlistEnabled: course.WaitlistEnabled != nil && *course.WaitlistEnabled,
+			})
+		}
+		return response.NewPage(res, info), nil
+	}
+}
+
+func (c *courseEndpoint) JoinWaitlist() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.CourseRegistrationRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		entry, err := c.courseService.JoinWaitlist(ctx, req.CourseId, req.StudentId)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Id == 0 {
+			return response.Message{Message: "A seat was free, registered"}, nil
+		}
+		return toWaitlistEntryResponse(entry), nil
+	}
+}
+
+func (c *courseEndpoint) LeaveWaitlist() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.CourseRegistrationRequest)
+		err := c.courseService.LeaveWaitlist(ctx, req.CourseId, req.StudentId)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Left waitlist"}, nil
+	}
+}
+
+func (c *courseEndpoint) AcceptWaitlistOffer() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.CourseRegistrationRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := c.courseService.AcceptWaitlistOffer(ctx, req.CourseId, req.StudentId)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Registered"}, nil
+	}
+}
+
+func (c *courseEndpoint) GetWaitlist() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		entries, err := c.courseService.GetWaitlist(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.WaitlistEntryResponse
+		for _, entry := range entries {
+			res = append(res, toWaitlistEntryResponse(entry))
+		}
+		return res, nil
+	}
+}
+
+func toWaitlistEntryResponse(entry model.WaitlistEntry) response.WaitlistEntryResponse {
+	return response.WaitlistEntryResponse{
+		StudentId:      entry.StudentId,
+		StudentName:    entry.StudentName,
+		Position:       entry.Position,
+		Status:         entry.Status,
+		OfferExpiresAt: entry.OfferExpiresAt,
+	}
+}
+
+func (c *courseEndpoint) GetCourseHistory() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		changes, err := c.courseService.GetCourseHistory(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return toCourseStatusChangeResponses(changes), nil
+	}
+}
+
+func (c *courseEndpoint) SearchCourses() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.SearchCoursesParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		if req.Limit <= 0 || req.Limit > 20 {
+			req.Limit = 20
+		}
+		courses, info, err := c.courseService.SearchCourses(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.CatalogCourseResponse
+		for _, course := range courses {
+			courseRes := response.CatalogCourseResponse{
+				Id:              course.Id,
+				SubjectId:       course.SubjectId,
+				SubjectName:     course.SubjectName,
+				NumberOfCredit:  course.NumberOfCredit,
+				Major:           course.Major,
+				TeacherId:       course.TeacherId,
+				TeacherName:     course.TeacherName,
+				TermCode:        course.TermCode,
+				Status:          course.Status,
+				Capacity:        course.Capacity,
+				Size:            course.Size,
+				RemainingSeats:  course.RemainingSeats(),
+				WaitlistEnabled: course.WaitlistEnabled != nil && *course.WaitlistEnabled,
+				Schedules:       []response.ScheduleSummaryResponse{},
+			}
+			for _, schedule := range course.Schedules {
+				courseRes.Schedules = append(courseRes.Schedules, response.ScheduleSummaryResponse{
+					DayOfWeek: schedule.DayOfWeek,
+					StartTime: schedule.StartTime,
+					EndTime:   schedule.EndTime,
+					Room:      schedule.Room,
+					Timezone:  schedule.Timezone,
+				})
+			}
+			res = append(res, courseRes)
+		}
+		return response.NewPage(res, info), nil
+	}
+}
+
+func (c *courseEndpoint) GetCourseRoster() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.GetCourseRosterParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		students, err := c.courseService.GetCourseRoster(ctx, req.CourseId)
+		if err != nil {
+			return nil, err
+		}
+		if req.Format == "csv" {
+			file := response.CsvFile{
+				FileName: req.CourseId + "-roster.csv",
+				Header:   []string{"id", "name", "email", "major", "school_year"},
+			}
+			for _, student := range students {
+				file.Rows = append(file.Rows, []string{student.Id, student.Name, student.Email, student.Major, student.SchoolYear})
+			}
+			return file, nil
+		}
+		res := []response.RosterStudentResponse{}
+		for _, student := range students {
+			res = append(res, response.RosterStudentResponse{
+				Id:         student.Id,
+				Name:       student.Name,
+				Email:      student.Email,
+				Major:      student.Major,
+				SchoolYear: student.SchoolYear,
 			})
 		}
 		return res, nil
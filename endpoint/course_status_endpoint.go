@@ -0,0 +1,80 @@
+package endpoint
+
+import (
+	"SchoolManagement/dto"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
+	"SchoolManagement/service"
+	"SchoolManagement/utils"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+type CourseStatusEndpoint interface {
+	PreviewTransitionsEndpoint() endpoint.Endpoint
+	GetTransitionLogEndpoint() endpoint.Endpoint
+}
+
+type courseStatusEndpoint struct {
+	courseStatusScheduler service.CourseStatusScheduler
+}
+
+func (c *courseStatusEndpoint) PreviewTransitionsEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.PreviewCourseStatusParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		date := time.Now()
+		if req.Date != "" {
+			date, _ = time.Parse(utils.DateLayout, req.Date)
+		}
+		changes, err := c.courseStatusScheduler.PreviewTransitions(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		return toCourseStatusChangeResponses(changes), nil
+	}
+}
+
+func (c *courseStatusEndpoint) GetTransitionLogEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.PaginationParams)
+		if req.Limit <= 0 || req.Limit > 20 {
+			req.Limit = 20
+		}
+		if req.Offset < 0 {
+			req.Offset = 0
+		}
+		changes, err := c.courseStatusScheduler.GetTransitionLog(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return toCourseStatusChangeResponses(changes), nil
+	}
+}
+
+func toCourseStatusChangeResponses(changes []model.CourseStatusChange) []response.CourseStatusChangeResponse {
+	res := []response.CourseStatusChangeResponse{}
+	for _, change := range changes {
+		res = append(res, response.CourseStatusChangeResponse{
+			CourseId:      change.CourseId,
+			FromStatus:    change.FromStatus,
+			ToStatus:      change.ToStatus,
+			Actor:         change.Actor,
+			Reason:        change.Reason,
+			CreatedAt:     change.CreatedAt,
+			BlockedReason: change.BlockedReason,
+		})
+	}
+	return res
+}
+
+func NewCourseStatusEndpoint(courseStatusScheduler service.CourseStatusScheduler) CourseStatusEndpoint {
+	return &courseStatusEndpoint{
+		courseStatusScheduler: courseStatusScheduler,
+	}
+}
@@ -0,0 +1,25 @@
+package endpoint
+
+import (
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type JwksEndpoint interface {
+	GetJwks() endpoint.Endpoint
+}
+
+type jwksEndpoint struct {
+	signingKeyRotator service.SigningKeyRotator
+}
+
+func (j *jwksEndpoint) GetJwks() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return j.signingKeyRotator.GetJwks(ctx), nil
+	}
+}
+
+func NewJwksEndpoint(signingKeyRotator service.SigningKeyRotator) JwksEndpoint {
+	return &jwksEndpoint{signingKeyRotator: signingKeyRotator}
+}
@@ -0,0 +1,69 @@
+package endpoint
+
+import (
+	request2 "SchoolManagement/dto/request"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+)
+
+type PasswordEndpoint interface {
+	ChangePassword() endpoint.Endpoint
+	ForgotPassword() endpoint.Endpoint
+	ResetPassword() endpoint.Endpoint
+}
+
+type passwordEndpoint struct {
+	passwordService service.PasswordService
+}
+
+func (p *passwordEndpoint) ChangePassword() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.ChangePasswordRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := p.passwordService.ChangePassword(ctx, req.CurrentPassword, req.NewPassword)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Password changed successfully, please log in again"}, nil
+	}
+}
+
+func (p *passwordEndpoint) ForgotPassword() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.ForgotPasswordRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := p.passwordService.ForgotPassword(ctx, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "If the email is registered, a password reset token has been sent to it"}, nil
+	}
+}
+
+func (p *passwordEndpoint) ResetPassword() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.ResetPasswordRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := p.passwordService.ResetPassword(ctx, req.Token, req.NewPassword)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Password reset successfully"}, nil
+	}
+}
+
+func NewPasswordEndpoint(passwordService service.PasswordService) PasswordEndpoint {
+	return &passwordEndpoint{passwordService: passwordService}
+}
@@ -0,0 +1,139 @@
+package endpoint
+
+import (
+	"SchoolManagement/dto"
+	request2 "SchoolManagement/dto/request"
+	"SchoolManagement/dto/response"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+type RoomEndpoint interface {
+	CreateRoomEndpoint() endpoint.Endpoint
+	UpdateRoomEndpoint() endpoint.Endpoint
+	DeleteRoomByIdEndpoint() endpoint.Endpoint
+	GetRoomByIdEndpoint() endpoint.Endpoint
+	GetRoomListEndpoint() endpoint.Endpoint
+	FindFreeRoomsEndpoint() endpoint.Endpoint
+}
+
+type roomEndpoint struct {
+	roomService service.RoomService
+}
+
+func (r *roomEndpoint) CreateRoomEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.RoomRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := r.roomService.CreateRoom(ctx, req.ToRoom())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Room created successfully"}, nil
+	}
+}
+
+func (r *roomEndpoint) UpdateRoomEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.RoomRequest)
+		validate := validator.New()
+		if err := validate.StructPartial(req, "Id", "Features"); err != nil {
+			return nil, err
+		}
+		if req.Capacity < 0 {
+			return nil, &error2.InvalidInputErr{Message: "capacity must be greater than 0"}
+		}
+		err := r.roomService.UpdateRoom(ctx, req.ToRoom())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Room updated successfully"}, nil
+	}
+}
+
+func (r *roomEndpoint) DeleteRoomByIdEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := r.roomService.DeleteRoomById(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Room deleted successfully"}, nil
+	}
+}
+
+func (r *roomEndpoint) GetRoomByIdEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		room, err := r.roomService.GetRoomById(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return toRoomResponse(room), nil
+	}
+}
+
+func (r *roomEndpoint) GetRoomListEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.GetRoomsParams)
+		if req.Limit <= 0 || req.Limit > 20 {
+			req.Limit = 20
+		}
+		if req.Offset < 0 {
+			req.Offset = 0
+		}
+		rooms, err := r.roomService.GetRoomList(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.RoomResponse
+		for _, room := range rooms {
+			res = append(res, toRoomResponse(room))
+		}
+		return res, nil
+	}
+}
+
+func (r *roomEndpoint) FindFreeRoomsEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.FindFreeRoomsParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		from, _ := time.Parse(time.RFC3339, req.From)
+		to, _ := time.Parse(time.RFC3339, req.To)
+		rooms, err := r.roomService.FindFreeRooms(ctx, from, to, req.MinCapacity, req.Features)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.RoomResponse
+		for _, room := range rooms {
+			res = append(res, toRoomResponse(room))
+		}
+		return res, nil
+	}
+}
+
+func toRoomResponse(room model.Room) response.RoomResponse {
+	return response.RoomResponse{
+		Id:       room.Id,
+		Building: room.Building,
+		Capacity: room.Capacity,
+		Features: room.Features,
+	}
+}
+
+func NewRoomEndpoint(roomService service.RoomService) RoomEndpoint {
+	return &roomEndpoint{
+		roomService: roomService,
+	}
+}
@@ -0,0 +1,145 @@
+package endpoint
+
+import (
+	request2 "SchoolManagement/dto/request"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+)
+
+type ScoreEndpoint interface {
+	SetComponentScores() endpoint.Endpoint
+	GetComponentScores() endpoint.Endpoint
+	RecordStudentScores() endpoint.Endpoint
+	GetStudentScores() endpoint.Endpoint
+	PreviewFinalGrades() endpoint.Endpoint
+	FinalizeGrades() endpoint.Endpoint
+	ReopenGrades() endpoint.Endpoint
+}
+
+type scoreEndpoint struct {
+	scoreService service.ScoreService
+}
+
+func (s *scoreEndpoint) SetComponentScores() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.ComponentScoresRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := s.scoreService.SetComponentScores(ctx, req.CourseId, req.ToComponentScores())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Score components updated"}, nil
+	}
+}
+
+func (s *scoreEndpoint) GetComponentScores() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		components, err := s.scoreService.GetComponentScores(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.ComponentScoreResponse
+		for _, component := range components {
+			res = append(res, response.ComponentScoreResponse{
+				Id:          component.Id,
+				Name:        component.Name,
+				ScoreWeight: component.ScoreWeight,
+			})
+		}
+		return res, nil
+	}
+}
+
+func (s *scoreEndpoint) RecordStudentScores() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.StudentScoresRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := s.scoreService.RecordStudentScores(ctx, req.CourseId, req.ToStudentScores())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Scores recorded"}, nil
+	}
+}
+
+func (s *scoreEndpoint) GetStudentScores() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		scores, err := s.scoreService.GetStudentScores(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.StudentScoresResponse
+		for _, score := range scores {
+			if len(res) == 0 || res[len(res)-1].StudentId != score.StudentId {
+				res = append(res, response.StudentScoresResponse{StudentId: score.StudentId})
+			}
+			res[len(res)-1].Scores = append(res[len(res)-1].Scores, response.ComponentScoreValue{
+				ComponentId:   score.ComponentId,
+				ComponentName: score.ComponentName,
+				Score:         score.Score,
+			})
+		}
+		return res, nil
+	}
+}
+
+func (s *scoreEndpoint) PreviewFinalGrades() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		registrations, err := s.scoreService.PreviewFinalGrades(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.FinalGradeResponse
+		for _, registration := range registrations {
+			res = append(res, response.FinalGradeResponse{
+				StudentId:         registration.StudentId,
+				FinalScore:        registration.FinalScore,
+				LetterGrade:       registration.LetterGrade,
+				GradePoint:        registration.GradePoint,
+				GradeFinalized:    registration.GradeFinalized,
+				MissingComponents: registration.MissingComponents,
+			})
+		}
+		return res, nil
+	}
+}
+
+func (s *scoreEndpoint) FinalizeGrades() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := s.scoreService.FinalizeGrades(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Grades finalized"}, nil
+	}
+}
+
+func (s *scoreEndpoint) ReopenGrades() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := s.scoreService.ReopenGrades(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Grades reopened"}, nil
+	}
+}
+
+func NewScoreEndpoint(scoreService service.ScoreService) ScoreEndpoint {
+	return &scoreEndpoint{
+		scoreService: scoreService,
+	}
+}
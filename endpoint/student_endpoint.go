@@ -1,8 +1,10 @@
 package endpoint
 
 import (
+	"SchoolManagement/dto"
 	request2 "SchoolManagement/dto/request"
 	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
 	"SchoolManagement/service"
 	"context"
 	"github.com/go-kit/kit/endpoint"
@@ -15,6 +17,8 @@ type StudentEndpoint interface {
 	UpdateStudentEndpoint() endpoint.Endpoint
 	DeleteStudentByIdEndpoint() endpoint.Endpoint
 	GetStudentByIdEndpoint() endpoint.Endpoint
+	GetTranscriptEndpoint() endpoint.Endpoint
+	GetStudentListEndpoint() endpoint.Endpoint
 }
 
 type studentEndpoint struct {
@@ -73,18 +77,85 @@ func (s *studentEndpoint) GetStudentByIdEndpoint() endpoint.Endpoint {
 		if err != nil {
 			return nil, err
 		}
-		return response.GetStudentResponse{
-			Id:             student.Id,
-			Name:           student.Name,
-			DateOfBirth:    student.DateOfBirth,
-			Gender:         student.Gender,
-			Email:          student.Email,
-			IdentityNumber: student.IdentityNumber,
-			PhoneNumber:    student.PhoneNumber,
-			Address:        student.Address,
-			SchoolYear:     student.SchoolYear,
-			Major:          student.Major,
-		}, nil
+		return toGetStudentResponse(student), nil
+	}
+}
+
+func (s *studentEndpoint) GetStudentListEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.GetStudentsParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		if req.Limit <= 0 || req.Limit > 20 {
+			req.Limit = 20
+		}
+		if req.Offset < 0 {
+			req.Offset = 0
+		}
+		students, info, err := s.studentService.GetStudentList(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.GetStudentResponse
+		for _, student := range students {
+			res = append(res, toGetStudentResponse(student))
+		}
+		return response.NewPage(res, info), nil
+	}
+}
+
+func toGetStudentResponse(student model.Student) response.GetStudentResponse {
+	return response.GetStudentResponse{
+		Id:             student.Id,
+		Name:           student.Name,
+		DateOfBirth:    student.DateOfBirth,
+		Gender:         student.Gender,
+		Email:          student.Email,
+		IdentityNumber: student.IdentityNumber,
+		PhoneNumber:    student.PhoneNumber,
+		Address:        student.Address,
+		SchoolYear:     student.SchoolYear,
+		Major:          student.Major,
+	}
+}
+
+func (s *studentEndpoint) GetTranscriptEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		transcript, err := s.studentService.GetTranscript(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		res := response.TranscriptResponse{
+			StudentId:          transcript.StudentId,
+			Terms:              []response.TranscriptTermResponse{},
+			CumulativeGpa:      transcript.CumulativeGpa,
+			TotalEarnedCredits: transcript.TotalEarnedCredits,
+		}
+		for _, term := range transcript.Terms {
+			termRes := response.TranscriptTermResponse{
+				TermCode:       term.TermCode,
+				AcademicYear:   term.AcademicYear,
+				SemesterNumber: term.SemesterNumber,
+				Credits:        term.Credits,
+				TermGpa:        term.Gpa,
+			}
+			for _, course := range term.Courses {
+				termRes.Courses = append(termRes.Courses, response.TranscriptCourseResponse{
+					CourseId:       course.CourseId,
+					SubjectId:      course.SubjectId,
+					SubjectName:    course.SubjectName,
+					NumberOfCredit: course.NumberOfCredit,
+					FinalScore:     course.FinalScore,
+					LetterGrade:    course.LetterGrade,
+					GradePoint:     course.GradePoint,
+				})
+			}
+			res.Terms = append(res.Terms, termRes)
+		}
+		return res, nil
 	}
 }
 
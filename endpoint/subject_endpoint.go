@@ -2,6 +2,7 @@ package endpoint
 
 import (
 	"SchoolManagement/dto"
+	request2 "SchoolManagement/dto/request"
 	"SchoolManagement/dto/response"
 	"SchoolManagement/service"
 	"context"
@@ -15,6 +16,10 @@ type SubjectEndpoint interface {
 	DeleteSubjectByIdEndpoint() endpoint.Endpoint
 	GetSubjectByIdEndpoint() endpoint.Endpoint
 	GetSubjectListEndpoint() endpoint.Endpoint
+	AddPrerequisiteEndpoint() endpoint.Endpoint
+	UpdatePrerequisiteEndpoint() endpoint.Endpoint
+	DeletePrerequisiteEndpoint() endpoint.Endpoint
+	GetPrerequisitesEndpoint() endpoint.Endpoint
 }
 
 type subjectEndpoint struct {
@@ -87,7 +92,7 @@ func (s *subjectEndpoint) GetSubjectListEndpoint() endpoint.Endpoint {
 		if req.Offset < 0 {
 			req.Offset = 0
 		}
-		subjects, err := s.subjectService.GetSubjectList(ctx, req)
+		subjects, info, err := s.subjectService.GetSubjectList(ctx, req)
 		if err != nil {
 			return nil, err
 		}
@@ -100,7 +105,67 @@ func (s *subjectEndpoint) GetSubjectListEndpoint() endpoint.Endpoint {
 				Major:          subject.Major,
 			})
 		}
-		return subjectList, nil
+		return response.NewPage(subjectList, info), nil
+	}
+}
+
+func (s *subjectEndpoint) AddPrerequisiteEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.SubjectPrerequisiteRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := s.subjectService.AddPrerequisite(ctx, req.ToSubjectPrerequisite())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Prerequisite added successfully"}, nil
+	}
+}
+
+func (s *subjectEndpoint) UpdatePrerequisiteEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.SubjectPrerequisiteRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := s.subjectService.UpdatePrerequisite(ctx, req.ToSubjectPrerequisite())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Prerequisite updated successfully"}, nil
+	}
+}
+
+func (s *subjectEndpoint) DeletePrerequisiteEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.SubjectPrerequisiteRequest)
+		err := s.subjectService.DeletePrerequisite(ctx, req.SubjectId, req.PrerequisiteId)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Prerequisite deleted successfully"}, nil
+	}
+}
+
+func (s *subjectEndpoint) GetPrerequisitesEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		prerequisites, err := s.subjectService.GetPrerequisites(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.SubjectPrerequisiteResponse
+		for _, prerequisite := range prerequisites {
+			res = append(res, response.SubjectPrerequisiteResponse{
+				PrerequisiteId:   prerequisite.PrerequisiteId,
+				PrerequisiteName: prerequisite.PrerequisiteName,
+				Type:             prerequisite.Type,
+			})
+		}
+		return res, nil
 	}
 }
 
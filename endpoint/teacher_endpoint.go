@@ -1,8 +1,10 @@
 package endpoint
 
 import (
+	"SchoolManagement/dto"
 	request2 "SchoolManagement/dto/request"
 	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
 	"SchoolManagement/service"
 	"context"
 	"github.com/go-kit/kit/endpoint"
@@ -15,6 +17,7 @@ type TeacherEndpoint interface {
 	UpdateTeacherEndpoint() endpoint.Endpoint
 	GetTeacherByIdEndpoint() endpoint.Endpoint
 	DeleteTeacherByIdEndpoint() endpoint.Endpoint
+	GetTeacherListEndpoint() endpoint.Endpoint
 }
 
 type teacherEndpoint struct {
@@ -62,19 +65,48 @@ func (t *teacherEndpoint) GetTeacherByIdEndpoint() endpoint.Endpoint {
 		if err != nil {
 			return nil, err
 		}
-		return response.GetTeacherResponse{
-			Id:                    teacher.Id,
-			Name:                  teacher.Name,
-			DateOfBirth:           teacher.DateOfBirth,
-			Gender:                teacher.Gender,
-			Email:                 teacher.Email,
-			IdentityNumber:        teacher.IdentityNumber,
-			PhoneNumber:           teacher.PhoneNumber,
-			Address:               teacher.Address,
-			Role:                  teacher.Role,
-			AcademicQualification: teacher.AcademicQualification,
-			Department:            teacher.Department,
-		}, nil
+		return toGetTeacherResponse(teacher), nil
+	}
+}
+
+func (t *teacherEndpoint) GetTeacherListEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.GetTeachersParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		if req.Limit <= 0 || req.Limit > 20 {
+			req.Limit = 20
+		}
+		if req.Offset < 0 {
+			req.Offset = 0
+		}
+		teachers, info, err := t.teacherService.GetTeacherList(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.GetTeacherResponse
+		for _, teacher := range teachers {
+			res = append(res, toGetTeacherResponse(teacher))
+		}
+		return response.NewPage(res, info), nil
+	}
+}
+
+func toGetTeacherResponse(teacher model.Teacher) response.GetTeacherResponse {
+	return response.GetTeacherResponse{
+		Id:                    teacher.Id,
+		Name:                  teacher.Name,
+		DateOfBirth:           teacher.DateOfBirth,
+		Gender:                teacher.Gender,
+		Email:                 teacher.Email,
+		IdentityNumber:        teacher.IdentityNumber,
+		PhoneNumber:           teacher.PhoneNumber,
+		Address:               teacher.Address,
+		Role:                  teacher.Role,
+		AcademicQualification: teacher.AcademicQualification,
+		Department:            teacher.Department,
 	}
 }
 
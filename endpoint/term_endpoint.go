@@ -0,0 +1,120 @@
+package endpoint
+
+import (
+	request2 "SchoolManagement/dto/request"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+)
+
+type TermEndpoint interface {
+	CreateTermEndpoint() endpoint.Endpoint
+	UpdateTermEndpoint() endpoint.Endpoint
+	DeleteTermByCodeEndpoint() endpoint.Endpoint
+	GetTermByCodeEndpoint() endpoint.Endpoint
+	GetTermListEndpoint() endpoint.Endpoint
+	GetCurrentTermEndpoint() endpoint.Endpoint
+}
+
+type termEndpoint struct {
+	termService service.TermService
+}
+
+func (t *termEndpoint) CreateTermEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TermRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := t.termService.CreateTerm(ctx, req.ToTerm())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Term created successfully"}, nil
+	}
+}
+
+func (t *termEndpoint) UpdateTermEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TermRequest)
+		validate := validator.New()
+		if err := validate.StructPartial(req, "Code"); err != nil {
+			return nil, err
+		}
+		err := t.termService.UpdateTerm(ctx, req.ToTerm())
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Term updated successfully"}, nil
+	}
+}
+
+func (t *termEndpoint) DeleteTermByCodeEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := t.termService.DeleteTermByCode(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Term deleted successfully"}, nil
+	}
+}
+
+func (t *termEndpoint) GetTermByCodeEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		term, err := t.termService.GetTermByCode(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return toTermResponse(term), nil
+	}
+}
+
+func (t *termEndpoint) GetTermListEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		terms, err := t.termService.GetTermList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var res []response.TermResponse
+		for _, term := range terms {
+			res = append(res, toTermResponse(term))
+		}
+		return res, nil
+	}
+}
+
+func (t *termEndpoint) GetCurrentTermEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		term, err := t.termService.GetCurrentTerm(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return toTermResponse(term), nil
+	}
+}
+
+func toTermResponse(term model.Term) response.TermResponse {
+	return response.TermResponse{
+		Code:              term.Code,
+		AcademicYear:      term.AcademicYear,
+		SemesterNumber:    term.SemesterNumber,
+		StartDate:         term.StartDate,
+		EndDate:           term.EndDate,
+		RegistrationOpen:  term.RegistrationOpen,
+		RegistrationClose: term.RegistrationClose,
+		AddDropDeadline:   term.AddDropDeadline,
+		IsCurrent:         term.IsCurrent != nil && *term.IsCurrent,
+	}
+}
+
+func NewTermEndpoint(termService service.TermService) TermEndpoint {
+	return &termEndpoint{
+		termService: termService,
+	}
+}
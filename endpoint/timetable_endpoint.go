@@ -0,0 +1,66 @@
+package endpoint
+
+import (
+	"SchoolManagement/dto"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/service"
+	"SchoolManagement/utils"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+type TimetableEndpoint interface {
+	GetWeeklyTimetableEndpoint() endpoint.Endpoint
+}
+
+type timetableEndpoint struct {
+	timetableService service.TimetableService
+}
+
+func (t *timetableEndpoint) GetWeeklyTimetableEndpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(dto.GetTimetableParams)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		week := time.Now()
+		if req.Week != "" {
+			week, _ = time.Parse(utils.DateLayout, req.Week)
+		}
+		timetable, err := t.timetableService.GetWeeklyTimetable(ctx, req.UserId, week)
+		if err != nil {
+			return nil, err
+		}
+		res := response.TimetableResponse{
+			UserId:    timetable.UserId,
+			WeekStart: timetable.WeekStart,
+		}
+		for _, day := range timetable.Days {
+			dayRes := response.TimetableDayResponse{
+				Date:      day.Date,
+				DayOfWeek: day.DayOfWeek,
+				Entries:   []response.TimetableEntryResponse{},
+			}
+			for _, entry := range day.Entries {
+				dayRes.Entries = append(dayRes.Entries, response.TimetableEntryResponse{
+					CourseId:    entry.CourseId,
+					SubjectName: entry.SubjectName,
+					TeacherName: entry.TeacherName,
+					Room:        entry.Room,
+					StartTime:   entry.StartTime,
+					EndTime:     entry.EndTime,
+					Timezone:    entry.Timezone,
+				})
+			}
+			res.Days = append(res.Days, dayRes)
+		}
+		return res, nil
+	}
+}
+
+func NewTimetableEndpoint(timetableService service.TimetableService) TimetableEndpoint {
+	return &timetableEndpoint{timetableService: timetableService}
+}
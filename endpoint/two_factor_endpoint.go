@@ -0,0 +1,108 @@
+package endpoint
+
+import (
+	request2 "SchoolManagement/dto/request"
+	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
+	"SchoolManagement/service"
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-playground/validator/v10"
+)
+
+type TwoFactorEndpoint interface {
+	Enroll() endpoint.Endpoint
+	Confirm() endpoint.Endpoint
+	Disable() endpoint.Endpoint
+	ResetUser() endpoint.Endpoint
+	GetPolicies() endpoint.Endpoint
+	SavePolicy() endpoint.Endpoint
+}
+
+type twoFactorEndpoint struct {
+	twoFactorService service.TwoFactorService
+}
+
+func (t *twoFactorEndpoint) Enroll() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		res, err := t.twoFactorService.Enroll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func (t *twoFactorEndpoint) Confirm() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TwoFactorCodeRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		res, err := t.twoFactorService.Confirm(ctx, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func (t *twoFactorEndpoint) Disable() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TwoFactorCodeRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := t.twoFactorService.Disable(ctx, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Two-factor authentication disabled successfully"}, nil
+	}
+}
+
+func (t *twoFactorEndpoint) ResetUser() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(string)
+		err := t.twoFactorService.ResetUser(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Two-factor authentication reset successfully"}, nil
+	}
+}
+
+func (t *twoFactorEndpoint) GetPolicies() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		policies, err := t.twoFactorService.GetPolicies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res := []response.TwoFactorPolicyResponse{}
+		for _, policy := range policies {
+			res = append(res, response.TwoFactorPolicyResponse{Role: policy.Role, Required: policy.Required})
+		}
+		return res, nil
+	}
+}
+
+func (t *twoFactorEndpoint) SavePolicy() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(request2.TwoFactorPolicyRequest)
+		validate := validator.New()
+		if err := validate.Struct(req); err != nil {
+			return nil, err
+		}
+		err := t.twoFactorService.SavePolicy(ctx, model.TwoFactorPolicy{Role: req.Role, Required: *req.Required})
+		if err != nil {
+			return nil, err
+		}
+		return response.Message{Message: "Two-factor policy updated successfully"}, nil
+	}
+}
+
+func NewTwoFactorEndpoint(twoFactorService service.TwoFactorService) TwoFactorEndpoint {
+	return &twoFactorEndpoint{twoFactorService: twoFactorService}
+}
@@ -3,11 +3,18 @@ package error
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var WrongPasswordErr = errors.New("wrong password")
+var InvalidCredentialsErr = errors.New("wrong id or password")
+var TooManyLoginAttemptsErr = errors.New("too many failed login attempts, try again later")
+var WrongTwoFactorCodeErr = errors.New("wrong two-factor code")
 var CourseLimitExceededErr = errors.New("course is full")
 var CourseRegisterTimoutErr = errors.New("course is not open for register or unregister")
+var GradesFinalizedErr = errors.New("course grades are finalized")
+var CourseNotCompleteErr = errors.New("course is not complete")
+var GradesIncompleteErr = errors.New("course grades are incomplete, every registered student needs a score for every component and the weights must add up to 100")
 
 type ResourceNotFoundErr struct {
 	Resource string
@@ -40,3 +47,30 @@ type InvalidInputErr struct {
 func (e *InvalidInputErr) Error() string {
 	return fmt.Sprintf("Invalid input: %s", e.Message)
 }
+
+type MissingPrerequisitesErr struct {
+	Subjects []string
+}
+
+func (e *MissingPrerequisitesErr) Error() string {
+	return fmt.Sprintf("Missing prerequisites: %s", strings.Join(e.Subjects, ", "))
+}
+
+type ScheduleConflictErr struct {
+	Message     string
+	ScheduleIds []int
+}
+
+func (e *ScheduleConflictErr) Error() string {
+	return fmt.Sprintf("Schedule conflict: %s", e.Message)
+}
+
+type StatusTransitionErr struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *StatusTransitionErr) Error() string {
+	return fmt.Sprintf("Cannot move course from %s to %s: %s", e.From, e.To, e.Reason)
+}
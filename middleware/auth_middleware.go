@@ -2,11 +2,13 @@ package middleware
 
 import (
 	"SchoolManagement/dto/response"
+	"SchoolManagement/repo/redis"
 	"SchoolManagement/utils"
 	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"math"
 	"net/http"
 	"strings"
 )
@@ -21,7 +23,8 @@ const (
 )
 
 type authMiddleware struct {
-	jwtService utils.JwtUtils
+	jwtService   utils.JwtUtils
+	sessionStore redis.SessionStore
 }
 
 func (a *authMiddleware) CheckUserAuthorities(ctx context.Context, r ...string) error {
@@ -47,7 +50,7 @@ func (a *authMiddleware) ValidateAndExtractJwt() gin.HandlerFunc {
 			return
 		}
 		header := strings.Fields(authHeader)
-		if len(header) != 2 && header[0] != "Bearer" {
+		if len(header) != 2 || header[0] != "Bearer" {
 			c.AbortWithStatusJSON(http.StatusBadRequest, response.Message{Error: "wrong access token format"})
 			return
 		}
@@ -55,6 +58,16 @@ func (a *authMiddleware) ValidateAndExtractJwt() gin.HandlerFunc {
 		claims, err := a.jwtService.VerifyToken(accessToken)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Message{Error: err.Error()})
+			return
+		}
+		revoked, err := a.isRevoked(c.Request.Context(), claims)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.Message{Error: "internal server error"})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Message{Error: "token has been revoked"})
+			return
 		}
 
 		ctx := context.WithValue(c.Request.Context(), JWTClaimsContextKey, claims)
@@ -63,6 +76,26 @@ func (a *authMiddleware) ValidateAndExtractJwt() gin.HandlerFunc {
 	}
 }
 
-func NewAuthMiddleware(jwtService utils.JwtUtils) AuthMiddleware {
-	return &authMiddleware{jwtService: jwtService}
+// isRevoked reports whether the token was logged out or issued before its user's sessions were revoked. Tokens
+// without a jti predate revocation support and are refused.
+func (a *authMiddleware) isRevoked(ctx context.Context, claims jwt.MapClaims) (bool, error) {
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return true, nil
+	}
+	denied, err := a.sessionStore.IsTokenDenied(ctx, jti)
+	if err != nil || denied {
+		return denied, err
+	}
+	userId, _ := claims["userId"].(string)
+	revokedAt, found, err := a.sessionStore.GetUserSessionsRevokedAt(ctx, userId)
+	if err != nil || !found {
+		return false, err
+	}
+	issuedAt, _ := claims["iat"].(float64)
+	return int64(math.Round(issuedAt*1000)) <= revokedAt, nil
+}
+
+func NewAuthMiddleware(jwtService utils.JwtUtils, sessionStore redis.SessionStore) AuthMiddleware {
+	return &authMiddleware{jwtService: jwtService, sessionStore: sessionStore}
 }
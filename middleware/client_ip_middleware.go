@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ClientIpContextKey = "ClientIpContextKey"
+)
+
+// ExtractClientIp puts the address of the connecting client in the request context. Forwarding headers are ignored
+// because anybody can set them.
+func ExtractClientIp() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), ClientIpContextKey, c.RemoteIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
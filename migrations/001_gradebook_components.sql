@@ -0,0 +1,19 @@
+-- Reshapes component_scores from the original schema, where a component carried a single score of its own, into
+-- the weighted components of a course gradebook. Per-student scores live in student_scores, created by db.sql.
+-- Nothing wrote to the table before the gradebook, so the old score column is dropped without being carried over.
+BEGIN;
+
+ALTER TABLE component_scores DROP COLUMN IF EXISTS score;
+
+DELETE FROM component_scores WHERE name IS NULL OR score_weight IS NULL OR score_weight <= 0 OR score_weight > 100;
+DELETE FROM component_scores c USING component_scores d WHERE c.course_id = d.course_id AND c.name = d.name AND c.id > d.id;
+
+ALTER TABLE component_scores ALTER COLUMN name SET NOT NULL, ALTER COLUMN score_weight SET NOT NULL;
+
+ALTER TABLE component_scores DROP CONSTRAINT IF EXISTS component_scores_score_weight_check;
+ALTER TABLE component_scores ADD CONSTRAINT component_scores_score_weight_check CHECK (score_weight > 0 AND score_weight <= 100);
+
+ALTER TABLE component_scores DROP CONSTRAINT IF EXISTS component_scores_course_id_name_key;
+ALTER TABLE component_scores ADD CONSTRAINT component_scores_course_id_name_key UNIQUE (course_id, name) DEFERRABLE INITIALLY DEFERRED;
+
+COMMIT;
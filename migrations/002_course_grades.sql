@@ -0,0 +1,10 @@
+-- Adds the final grade columns of the grade computation to course_registrations.
+BEGIN;
+
+ALTER TABLE course_registrations
+    ADD COLUMN IF NOT EXISTS final_score DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS letter_grade TEXT,
+    ADD COLUMN IF NOT EXISTS grade_point DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS grade_finalized BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,55 @@
+-- Turns the course schedules of the original schema, a unix start and end time each, into weekly patterns that occur
+-- once: on the UTC weekday of the start, from the UTC time of the start to the UTC time of the end. Schedules
+-- without a start or an end could never be shown and are dropped. A pattern cannot cross midnight, so schedules that
+-- do not start and end on the same UTC day have to be split or fixed by hand first; the migration stops and lists
+-- them instead of converting them.
+BEGIN;
+
+DO $$
+DECLARE
+    crossing TEXT;
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'course_schedules' AND column_name = 'start_time' AND data_type = 'integer') THEN
+        DELETE FROM course_schedules WHERE start_time IS NULL OR end_time IS NULL;
+
+        SELECT string_agg(format('%s (course %s)', id, course_id), ', ' ORDER BY id) INTO crossing
+        FROM course_schedules
+        WHERE end_time <= start_time
+           OR (to_timestamp(end_time) AT TIME ZONE 'UTC')::date <> (to_timestamp(start_time) AT TIME ZONE 'UTC')::date;
+        IF crossing IS NOT NULL THEN
+            RAISE EXCEPTION 'course schedules do not start and end on the same UTC day: %', crossing;
+        END IF;
+
+        ALTER TABLE course_schedules RENAME COLUMN start_time TO start_unix;
+        ALTER TABLE course_schedules RENAME COLUMN end_time TO end_unix;
+        ALTER TABLE course_schedules
+            ADD COLUMN day_of_week INT,
+            ADD COLUMN start_time TEXT,
+            ADD COLUMN end_time TEXT,
+            ADD COLUMN start_date TEXT,
+            ADD COLUMN end_date TEXT,
+            ADD COLUMN timezone TEXT,
+            ADD COLUMN exception_dates TEXT[] NOT NULL DEFAULT '{}';
+
+        UPDATE course_schedules SET
+            day_of_week = EXTRACT(ISODOW FROM to_timestamp(start_unix) AT TIME ZONE 'UTC'),
+            start_time = to_char(to_timestamp(start_unix) AT TIME ZONE 'UTC', 'HH24:MI'),
+            end_time = to_char(to_timestamp(end_unix) AT TIME ZONE 'UTC', 'HH24:MI'),
+            start_date = to_char(to_timestamp(start_unix) AT TIME ZONE 'UTC', 'YYYY-MM-DD'),
+            end_date = to_char(to_timestamp(start_unix) AT TIME ZONE 'UTC', 'YYYY-MM-DD'),
+            timezone = 'UTC';
+
+        ALTER TABLE course_schedules DROP COLUMN start_unix, DROP COLUMN end_unix;
+        ALTER TABLE course_schedules
+            ALTER COLUMN day_of_week SET NOT NULL,
+            ALTER COLUMN start_time SET NOT NULL,
+            ALTER COLUMN end_time SET NOT NULL,
+            ALTER COLUMN start_date SET NOT NULL,
+            ALTER COLUMN end_date SET NOT NULL,
+            ALTER COLUMN timezone SET NOT NULL;
+        ALTER TABLE course_schedules ADD CONSTRAINT course_schedules_day_of_week_check CHECK (day_of_week BETWEEN 1 AND 7);
+    END IF;
+END $$;
+
+COMMIT;
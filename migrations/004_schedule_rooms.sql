@@ -0,0 +1,23 @@
+-- Makes course_schedules.room reference the room catalog. Rooms named in existing schedules that are not in the
+-- catalog are added with no building and the capacity of the largest course scheduled in them, or 1 when no course
+-- is found, so that every room referenced exists and the capacity rule holds for the schedules already there. Their
+-- building and capacity should be corrected afterwards.
+BEGIN;
+
+INSERT INTO rooms(id, building, capacity)
+SELECT course_schedules.room, '', GREATEST(COALESCE(MAX(courses.capacity), 1), 1)
+FROM course_schedules
+LEFT JOIN courses ON course_schedules.course_id = courses.id
+WHERE course_schedules.room IS NOT NULL
+GROUP BY course_schedules.room
+ON CONFLICT (id) DO NOTHING;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.table_constraints
+                   WHERE table_name = 'course_schedules' AND constraint_type = 'FOREIGN KEY' AND constraint_name = 'course_schedules_room_fkey') THEN
+        ALTER TABLE course_schedules ADD CONSTRAINT course_schedules_room_fkey FOREIGN KEY (room) REFERENCES rooms(id);
+    END IF;
+END $$;
+
+COMMIT;
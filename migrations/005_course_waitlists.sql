@@ -0,0 +1,7 @@
+-- Adds the waitlist switch to courses created before waitlists existed. The course_waitlists table itself comes
+-- from db.sql.
+BEGIN;
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS waitlist_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- Moves courses from the free-text academic_year and semester_number columns to a reference to terms. Each pair
+-- used by a course becomes a term coded "<academic_year>-<semester_number>", unless a term for the pair exists
+-- already. The dates of the new terms are unknown, they are all set to 9999-12-31 so that the scheduler leaves the
+-- courses where they are and registration stays open as before. Set the real dates through the term endpoints
+-- afterwards. Semester numbers outside 1 to 3 fail the terms check and abort the migration, fix them first.
+BEGIN;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'courses' AND column_name = 'academic_year') THEN
+        INSERT INTO terms(code, academic_year, semester_number, start_date, end_date, registration_open, registration_close, add_drop_deadline)
+        SELECT DISTINCT courses.academic_year || '-' || courses.semester_number, courses.academic_year, courses.semester_number,
+            '9999-12-31', '9999-12-31', '9999-12-31', '9999-12-31', '9999-12-31'
+        FROM courses
+        WHERE NOT EXISTS (SELECT 1 FROM terms
+                          WHERE terms.academic_year = courses.academic_year AND terms.semester_number = courses.semester_number)
+        ON CONFLICT (code) DO NOTHING;
+
+        ALTER TABLE courses ADD COLUMN IF NOT EXISTS term_code TEXT;
+        UPDATE courses SET term_code = terms.code
+        FROM terms
+        WHERE terms.academic_year = courses.academic_year AND terms.semester_number = courses.semester_number;
+
+        ALTER TABLE courses ALTER COLUMN term_code SET NOT NULL;
+        ALTER TABLE courses ADD CONSTRAINT courses_term_code_fkey FOREIGN KEY (term_code) REFERENCES terms(code);
+        ALTER TABLE courses DROP COLUMN academic_year, DROP COLUMN semester_number;
+    END IF;
+END $$;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Signing keys used to be stored as plaintext PEM. They cannot be loaded any more now that private keys are
+-- encrypted with JWT_KEY_ENCRYPTION_KEY, and they should not stay readable in the database either, so they are
+-- removed. The server generates a new key on start; access tokens signed with the old keys are rejected and clients
+-- get new ones with their refresh tokens.
+BEGIN;
+
+DELETE FROM jwt_signing_keys WHERE private_key LIKE '-----BEGIN%';
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- E-mail addresses are unique regardless of case. Accounts whose addresses differ only in case have to be merged or
+-- renamed by hand first; the migration stops and lists them instead of picking one.
+BEGIN;
+
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(emails, '; ') INTO duplicates
+    FROM (
+        SELECT string_agg(email || ' (' || id || ')', ', ' ORDER BY id) AS emails
+        FROM users
+        WHERE email IS NOT NULL
+        GROUP BY LOWER(email)
+        HAVING COUNT(*) > 1
+    ) AS groups;
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'users share an e-mail address that differs only in case: %', duplicates;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower ON users (LOWER(email));
+
+COMMIT;
@@ -0,0 +1,27 @@
+package model
+
+const (
+	AuthEventLoginSucceeded  string = "LoginSucceeded"
+	AuthEventLoginFailed     string = "LoginFailed"
+	AuthEventLoginBlocked    string = "LoginBlocked"
+	AuthEventAccountUnlocked string = "AccountUnlocked"
+	// AuthEventTwoFactorChallenged is recorded when the password was right and the second factor is asked for.
+	AuthEventTwoFactorChallenged string = "TwoFactorChallenged"
+	AuthEventTwoFactorFailed     string = "TwoFactorFailed"
+	AuthEventSsoLoginFailed      string = "SsoLoginFailed"
+	// AuthEventPasswordChangeFailed is recorded when a logged in user submits a wrong current password.
+	AuthEventPasswordChangeFailed string = "PasswordChangeFailed"
+	// AuthEventUserProvisioned is recorded when a single sign-on creates the user.
+	AuthEventUserProvisioned string = "UserProvisioned"
+)
+
+// AuthEvent records an authentication attempt. UserId is the id as it was submitted, so it may not belong to any
+// user.
+type AuthEvent struct {
+	Id        int    `db:"id"`
+	UserId    string `db:"user_id"`
+	IpAddress string `db:"ip_address"`
+	Event     string `db:"event"`
+	// CreatedAt is a unix timestamp.
+	CreatedAt int64 `db:"created_at"`
+}
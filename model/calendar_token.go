@@ -0,0 +1,8 @@
+package model
+
+// CalendarToken authenticates the calendar feed of a user. Only the hash of the token is stored.
+type CalendarToken struct {
+	UserId    string `db:"user_id"`
+	TokenHash string `db:"token_hash"`
+	CreatedAt int64  `db:"created_at"`
+}
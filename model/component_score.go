@@ -0,0 +1,10 @@
+package model
+
+const MaxScore float64 = 10
+
+type ComponentScore struct {
+	Id          int     `db:"id"`
+	CourseId    string  `db:"course_id"`
+	Name        string  `db:"name"`
+	ScoreWeight float64 `db:"score_weight"`
+}
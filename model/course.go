@@ -8,14 +8,33 @@ const (
 )
 
 type Course struct {
-	Id             string `db:"id"`
-	TeacherId      string `db:"teacher_id"`
-	TeacherName    string `db:"teacher_name"`
-	SubjectId      string `db:"subject_id"`
-	SubjectName    string `db:"subject_name"`
+	Id          string `db:"id"`
+	TeacherId   string `db:"teacher_id"`
+	TeacherName string `db:"teacher_name"`
+	SubjectId   string `db:"subject_id"`
+	SubjectName string `db:"subject_name"`
+	TermCode    string `db:"term_code"`
+	// SemesterNumber and AcademicYear are read from the course term.
 	SemesterNumber int    `db:"semester_number"`
 	AcademicYear   string `db:"academic_year"`
 	Capacity       int    `db:"capacity"`
 	Size           int    `db:"size"`
 	Status         string `db:"status"`
+	// WaitlistEnabled is a pointer so that partial updates can switch the waitlist off.
+	WaitlistEnabled *bool `db:"waitlist_enabled"`
+}
+
+// CatalogCourse is a course as listed in the course catalog, with its subject details and weekly schedules.
+type CatalogCourse struct {
+	Course
+	NumberOfCredit int              `db:"number_of_credit"`
+	Major          string           `db:"major"`
+	Schedules      []CourseSchedule `db:"-"`
+}
+
+func (c CatalogCourse) RemainingSeats() int {
+	if c.Size >= c.Capacity {
+		return 0
+	}
+	return c.Capacity - c.Size
 }
@@ -1,7 +1,13 @@
 package model
 
 type CourseRegistration struct {
-	Id        int    `db:"id"`
-	CourseId  string `db:"course_id"`
-	StudentId string `db:"student_id"`
+	Id             int     `db:"id"`
+	CourseId       string  `db:"course_id"`
+	StudentId      string  `db:"student_id"`
+	FinalScore     float64 `db:"final_score"`
+	LetterGrade    string  `db:"letter_grade"`
+	GradePoint     float64 `db:"grade_point"`
+	GradeFinalized bool    `db:"grade_finalized"`
+	// MissingComponents names the components the student has no score for yet. It is only set on computed grades.
+	MissingComponents []string `db:"-"`
 }
@@ -1,9 +1,27 @@
 package model
 
+import "github.com/lib/pq"
+
+// CourseSchedule is a weekly recurring pattern. DayOfWeek goes from 1 (Monday) to 7 (Sunday),
+// StartTime/EndTime are local "15:04" times in Timezone and dates use the "2006-01-02" layout.
 type CourseSchedule struct {
-	Id        int    `db:"id"`
-	CourseId  string `db:"course_id"`
-	Room      string `db:"room"`
-	StartTime int64  `db:"start_time"`
-	EndTime   int64  `db:"end_time"`
+	Id             int            `db:"id"`
+	CourseId       string         `db:"course_id"`
+	Room           string         `db:"room"`
+	DayOfWeek      int            `db:"day_of_week"`
+	StartTime      string         `db:"start_time"`
+	EndTime        string         `db:"end_time"`
+	StartDate      string         `db:"start_date"`
+	EndDate        string         `db:"end_date"`
+	Timezone       string         `db:"timezone"`
+	ExceptionDates pq.StringArray `db:"exception_dates"`
+}
+
+// CourseSession is one concrete occurrence of a CourseSchedule, with unix start and end times.
+type CourseSession struct {
+	ScheduleId int
+	CourseId   string
+	Room       string
+	StartTime  int64
+	EndTime    int64
 }
@@ -0,0 +1,34 @@
+package model
+
+const CourseStatusActorScheduler string = "scheduler"
+
+// CourseStatusTransitions lists the statuses a course may move to from each status.
+var CourseStatusTransitions = map[string][]string{
+	CourseStatusInitial:  {CourseStatusRegister},
+	CourseStatusRegister: {CourseStatusInitial, CourseStatusOngoing},
+	CourseStatusOngoing:  {CourseStatusComplete},
+	CourseStatusComplete: {},
+}
+
+type CourseStatusChange struct {
+	Id         int    `db:"id"`
+	CourseId   string `db:"course_id"`
+	FromStatus string `db:"from_status"`
+	ToStatus   string `db:"to_status"`
+	Actor      string `db:"actor"`
+	Reason     string `db:"reason"`
+	// CreatedAt is a unix timestamp.
+	CreatedAt int64 `db:"created_at"`
+	// BlockedReason is set by the scheduler preview when the change would be rejected.
+	BlockedReason string `db:"-"`
+}
+
+// CourseCalendar holds the term dates that drive the automatic status changes of a course.
+type CourseCalendar struct {
+	CourseId         string `db:"course_id"`
+	Status           string `db:"status"`
+	TermCode         string `db:"term_code"`
+	RegistrationOpen string `db:"registration_open"`
+	StartDate        string `db:"start_date"`
+	EndDate          string `db:"end_date"`
+}
@@ -0,0 +1,17 @@
+package model
+
+const (
+	WaitlistStatusWaiting string = "Waiting"
+	WaitlistStatusOffered string = "Offered"
+)
+
+type WaitlistEntry struct {
+	Id          int    `db:"id"`
+	CourseId    string `db:"course_id"`
+	StudentId   string `db:"student_id"`
+	StudentName string `db:"student_name"`
+	Position    int    `db:"position"`
+	Status      string `db:"status"`
+	// OfferExpiresAt is a unix timestamp, 0 while the student is still waiting.
+	OfferExpiresAt int64 `db:"offer_expires_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+type GradeLevel struct {
+	Letter     string
+	MinScore   float64
+	GradePoint float64
+}
+
+// GradeScale is ordered from the highest MinScore to the lowest.
+type GradeScale []GradeLevel
+
+// DefaultGradeScale converts the Vietnamese 10-point scale to letters and the 4-point scale.
+var DefaultGradeScale = GradeScale{
+	{Letter: "A", MinScore: 8.5, GradePoint: 4.0},
+	{Letter: "B+", MinScore: 8.0, GradePoint: 3.5},
+	{Letter: "B", MinScore: 7.0, GradePoint: 3.0},
+	{Letter: "C+", MinScore: 6.5, GradePoint: 2.5},
+	{Letter: "C", MinScore: 5.5, GradePoint: 2.0},
+	{Letter: "D+", MinScore: 5.0, GradePoint: 1.5},
+	{Letter: "D", MinScore: 4.0, GradePoint: 1.0},
+	{Letter: "F", MinScore: 0, GradePoint: 0},
+}
@@ -0,0 +1,8 @@
+package model
+
+// RefreshToken is the server side record of a refresh token. Every token rotated from the same login shares FamilyId.
+type RefreshToken struct {
+	UserId    string `json:"user_id"`
+	FamilyId  string `json:"family_id"`
+	ExpiresAt int64  `json:"expires_at"`
+}
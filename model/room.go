@@ -0,0 +1,15 @@
+package model
+
+import "github.com/lib/pq"
+
+const (
+	RoomFeatureProjector string = "projector"
+	RoomFeatureLab       string = "lab"
+)
+
+type Room struct {
+	Id       string         `db:"id"`
+	Building string         `db:"building"`
+	Capacity int            `db:"capacity"`
+	Features pq.StringArray `db:"features"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// SigningKey is a key access tokens are signed with, identified in the tokens by Kid. The newest active key signs,
+// the others only verify until they expire.
+type SigningKey struct {
+	Kid       string `db:"kid"`
+	Algorithm string `db:"algorithm"`
+	// PrivateKey is PEM encoded PKCS #8, encrypted with the key encryption key of the server.
+	PrivateKey string `db:"private_key"`
+	// ActivatesAt and ExpiresAt are unix timestamps, ExpiresAt is 0 until a newer key replaces this one.
+	ActivatesAt int64 `db:"activates_at"`
+	ExpiresAt   int64 `db:"expires_at"`
+}
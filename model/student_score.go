@@ -0,0 +1,9 @@
+package model
+
+type StudentScore struct {
+	Id            int     `db:"id"`
+	ComponentId   int     `db:"component_id"`
+	ComponentName string  `db:"component_name"`
+	StudentId     string  `db:"student_id"`
+	Score         float64 `db:"score"`
+}
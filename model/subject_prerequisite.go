@@ -0,0 +1,15 @@
+package model
+
+const (
+	PrerequisiteTypePrerequisite string = "Prerequisite"
+	PrerequisiteTypeCorequisite  string = "Corequisite"
+)
+
+// SubjectPrerequisite means SubjectId requires PrerequisiteId. A Prerequisite must be passed
+// beforehand, a Corequisite may also be taken in the same semester.
+type SubjectPrerequisite struct {
+	SubjectId        string `db:"subject_id"`
+	PrerequisiteId   string `db:"prerequisite_id"`
+	PrerequisiteName string `db:"prerequisite_name"`
+	Type             string `db:"type"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// Term is an academic term. All dates use the YYYY-MM-DD format.
+type Term struct {
+	Code              string `db:"code"`
+	AcademicYear      string `db:"academic_year"`
+	SemesterNumber    int    `db:"semester_number"`
+	StartDate         string `db:"start_date"`
+	EndDate           string `db:"end_date"`
+	RegistrationOpen  string `db:"registration_open"`
+	RegistrationClose string `db:"registration_close"`
+	AddDropDeadline   string `db:"add_drop_deadline"`
+	// IsCurrent is a pointer so that partial updates can clear the flag.
+	IsCurrent *bool `db:"is_current"`
+}
@@ -0,0 +1,24 @@
+package model
+
+type TimetableEntry struct {
+	CourseId    string
+	SubjectName string
+	TeacherName string
+	Room        string
+	StartTime   string
+	EndTime     string
+	Timezone    string
+}
+
+type TimetableDay struct {
+	Date      string
+	DayOfWeek int
+	Entries   []TimetableEntry
+}
+
+// Timetable is the weekly grid of a user, from Monday to Sunday.
+type Timetable struct {
+	UserId    string
+	WeekStart string
+	Days      []TimetableDay
+}
@@ -0,0 +1,30 @@
+package model
+
+type CourseGrade struct {
+	CourseId       string  `db:"course_id"`
+	SubjectId      string  `db:"subject_id"`
+	SubjectName    string  `db:"subject_name"`
+	NumberOfCredit int     `db:"number_of_credit"`
+	TermCode       string  `db:"term_code"`
+	SemesterNumber int     `db:"semester_number"`
+	AcademicYear   string  `db:"academic_year"`
+	FinalScore     float64 `db:"final_score"`
+	LetterGrade    string  `db:"letter_grade"`
+	GradePoint     float64 `db:"grade_point"`
+}
+
+type TranscriptTerm struct {
+	TermCode       string
+	AcademicYear   string
+	SemesterNumber int
+	Courses        []CourseGrade
+	Credits        int
+	Gpa            float64
+}
+
+type Transcript struct {
+	StudentId          string
+	Terms              []TranscriptTerm
+	CumulativeGpa      float64
+	TotalEarnedCredits int
+}
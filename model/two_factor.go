@@ -0,0 +1,24 @@
+package model
+
+// UserTotp is the TOTP secret of a user. It only protects logins once Enabled, which happens when the user proves
+// they have set up their authenticator with a first code.
+type UserTotp struct {
+	UserId  string `db:"user_id"`
+	Secret  string `db:"secret"`
+	Enabled bool   `db:"enabled"`
+	// LastUsedStep is the time step of the last accepted code, so that a code cannot be replayed.
+	LastUsedStep int64 `db:"last_used_step"`
+	CreatedAt    int64 `db:"created_at"`
+}
+
+type TwoFactorPolicy struct {
+	Role     string `db:"role"`
+	Required bool   `db:"required"`
+}
+
+// LoginChallenge is what a user that passed the password step of a login still has to complete.
+type LoginChallenge struct {
+	UserId string `json:"user_id"`
+	// Setup is set when the role of the user requires two-factor authentication that the user has not enabled yet.
+	Setup bool `json:"setup"`
+}
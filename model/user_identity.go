@@ -0,0 +1,30 @@
+package model
+
+// UserIdentity links the account of a user at an OpenID Connect provider, identified by the issuer and the subject
+// it assigns, to the user.
+type UserIdentity struct {
+	Issuer  string `db:"issuer"`
+	Subject string `db:"subject"`
+	UserId  string `db:"user_id"`
+	// CreatedAt is a unix timestamp.
+	CreatedAt int64 `db:"created_at"`
+}
+
+// OidcClaims are the claims of a verified ID token that users are matched and provisioned with.
+type OidcClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// Birthdate is in the YYYY-MM-DD format, or empty when the provider did not release it.
+	Birthdate string
+}
+
+// OidcLoginState is what a started single sign-on login needs to complete once the provider redirects back.
+type OidcLoginState struct {
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	// BrowserBindingHash is the hash of the cookie set on the browser that started the login.
+	BrowserBindingHash string `json:"browser_binding_hash"`
+}
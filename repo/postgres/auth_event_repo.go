@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"SchoolManagement/model"
+	"context"
+	"github.com/jmoiron/sqlx"
+	"log"
+)
+
+type AuthEventRepo interface {
+	InsertAuthEvent(ctx context.Context, event model.AuthEvent, tx *sqlx.Tx) error
+}
+
+type authEventRepo struct {
+	db *sqlx.DB
+}
+
+func (a *authEventRepo) InsertAuthEvent(ctx context.Context, event model.AuthEvent, tx *sqlx.Tx) error {
+	query := `INSERT INTO auth_events(user_id, ip_address, event, created_at) VALUES (:user_id, :ip_address, :event, :created_at)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, event)
+	} else {
+		_, err = a.db.NamedExecContext(ctx, query, event)
+	}
+	if err != nil {
+		log.Println("Auth event repo, insert auth event err: ", err)
+		return err
+	}
+	return nil
+}
+
+func NewAuthEventRepo(db *sqlx.DB) AuthEventRepo {
+	return &authEventRepo{db: db}
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"log"
+)
+
+type CalendarTokenRepo interface {
+	SaveCalendarToken(ctx context.Context, token model.CalendarToken, tx *sqlx.Tx) error
+	DeleteCalendarToken(ctx context.Context, userId string, tx *sqlx.Tx) error
+	GetCalendarTokenByHash(ctx context.Context, tokenHash string, tx *sqlx.Tx) (model.CalendarToken, error)
+}
+
+type calendarTokenRepo struct {
+	db *sqlx.DB
+}
+
+// SaveCalendarToken stores the token of the user, replacing the previous one.
+func (c *calendarTokenRepo) SaveCalendarToken(ctx context.Context, token model.CalendarToken, tx *sqlx.Tx) error {
+	query := `INSERT INTO calendar_tokens(user_id, token_hash, created_at) VALUES (:user_id, :token_hash, :created_at)
+			ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, token)
+	} else {
+		_, err = c.db.NamedExecContext(ctx, query, token)
+	}
+	if err != nil {
+		log.Println("Calendar token repo, save calendar token err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (c *calendarTokenRepo) DeleteCalendarToken(ctx context.Context, userId string, tx *sqlx.Tx) error {
+	query := `DELETE FROM calendar_tokens WHERE user_id = $1`
+
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, userId)
+	} else {
+		result, err = c.db.ExecContext(ctx, query, userId)
+	}
+	if err != nil {
+		log.Println("Calendar token repo, delete calendar token err: ", err)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Calendar token repo, delete calendar token err: ", err)
+		return err
+	}
+	if affected == 0 {
+		return &error2.ResourceNotFoundErr{Resource: "Calendar token"}
+	}
+	return nil
+}
+
+func (c *calendarTokenRepo) GetCalendarTokenByHash(ctx context.Context, tokenHash string, tx *sqlx.Tx) (model.CalendarToken, error) {
+	query := `SELECT user_id, token_hash, created_at FROM calendar_tokens WHERE token_hash = $1`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, tokenHash)
+	} else {
+		row = c.db.QueryRowxContext(ctx, query, tokenHash)
+	}
+	var token model.CalendarToken
+	err := row.StructScan(&token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, &error2.ResourceNotFoundErr{Resource: "Calendar token"}
+		}
+		log.Println("Calendar token repo, get calendar token err: ", err)
+		return token, err
+	}
+	return token, nil
+}
+
+func NewCalendarTokenRepo(db *sqlx.DB) CalendarTokenRepo {
+	return &calendarTokenRepo{db: db}
+}
@@ -1,11 +1,13 @@
 package postgres
 
 import (
+	"SchoolManagement/dto"
 	error2 "SchoolManagement/error"
 	"SchoolManagement/model"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"log"
@@ -24,8 +26,21 @@ type CourseRepo interface {
 	AddCourseSchedule(ctx context.Context, schedule model.CourseSchedule, tx *sqlx.Tx) error
 	GetCourseSchedulesByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseSchedule, error)
 	DeleteCourseScheduleById(ctx context.Context, id string, tx *sqlx.Tx) error
-	GetCoursesByUserId(ctx context.Context, userId string, role string, semester int, academicYear string, tx *sqlx.Tx) ([]model.Course, error)
+	GetCoursesByUserId(ctx context.Context, userId string, role string, termCode string, tx *sqlx.Tx) ([]model.Course, error)
+	GetCourseListByUserId(ctx context.Context, params dto.GetCoursesParams, role string, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Course, error)
+	CountCoursesByUserId(ctx context.Context, params dto.GetCoursesParams, role string, tx *sqlx.Tx) (int, error)
 	DecreaseCourseSize(ctx context.Context, courseId string, quantity int, tx *sqlx.Tx) error
+	GetCourseRegistration(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) (model.CourseRegistration, error)
+	GetCourseSchedulesByStudentId(ctx context.Context, studentId string, termCode string, tx *sqlx.Tx) ([]model.CourseSchedule, error)
+	LockCourseSchedules(ctx context.Context, tx *sqlx.Tx) error
+	GetLargestCourseByRoom(ctx context.Context, room string, tx *sqlx.Tx) (model.Course, error)
+	GetSchedulesByRoomInDateRange(ctx context.Context, room string, startDate string, endDate string, tx *sqlx.Tx) ([]model.CourseSchedule, error)
+	GetSchedulesByTeacherIdInDateRange(ctx context.Context, teacherId string, startDate string, endDate string, tx *sqlx.Tx) ([]model.CourseSchedule, error)
+	GetSchedulesInDateRange(ctx context.Context, startDate string, endDate string, tx *sqlx.Tx) ([]model.CourseSchedule, error)
+	GetSchedulesByCourseIds(ctx context.Context, courseIds []string, tx *sqlx.Tx) ([]model.CourseSchedule, error)
+	SearchCourses(ctx context.Context, params dto.SearchCoursesParams, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.CatalogCourse, error)
+	CountSearchCourses(ctx context.Context, params dto.SearchCoursesParams, tx *sqlx.Tx) (int, error)
+	GetStudentsByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.Student, error)
 }
 
 type courseRepo struct {
@@ -48,7 +63,10 @@ func (c *courseRepo) DecreaseCourseSize(ctx context.Context, courseId string, qu
 }
 
 func (c *courseRepo) GetCourseForUpdate(ctx context.Context, id string, tx *sqlx.Tx) (model.Course, error) {
-	query := `SELECT id, teacher_id, subject_id, semester_number, academic_year, capacity, size, status FROM courses WHERE id = $1 FOR UPDATE`
+	query := `SELECT courses.id, courses.teacher_id, courses.subject_id, courses.term_code, terms.semester_number, terms.academic_year, courses.capacity, courses.size, courses.status, courses.waitlist_enabled
+			FROM courses
+			JOIN terms ON courses.term_code = terms.code
+			WHERE courses.id = $1 FOR UPDATE OF courses`
 
 	var row *sqlx.Row
 	if tx != nil {
@@ -124,29 +142,36 @@ func (c *courseRepo) UpdateCourse(ctx context.Context, course model.Course, tx *
 	return nil
 }
 
-func (c *courseRepo) GetCoursesByUserId(ctx context.Context, userId string, role string, semester int, academicYear string, tx *sqlx.Tx) ([]model.Course, error) {
-	var query string
+const userCourseColumns = `SELECT courses.id, courses.teacher_id, users.name as teacher_name, courses.subject_id, subjects.name as subject_name, courses.term_code, terms.semester_number, terms.academic_year, courses.capacity, courses.size, courses.status, courses.waitlist_enabled`
+
+// userCoursesFrom selects the courses a student is registered to or a teacher teaches, with $1 the user id and $2 the term code.
+func userCoursesFrom(role string) string {
 	if role == model.RoleStudent {
-		query = `SELECT courses.id, users.name as teacher_name, subjects.name as subject_name, courses.semester_number, courses.academic_year, courses.capacity, courses.size, courses.status
+		return `
  				FROM course_registrations
  				JOIN courses ON course_registrations.course_id = courses.id
  				JOIN users ON courses.teacher_id = users.id
  				JOIN subjects ON courses.subject_id = subjects.id
- 				WHERE course_registrations.student_id = $1 AND courses.semester_number = $2 AND courses.academic_year = $3`
-	} else {
-		query = `SELECT courses.id, users.name as teacher_name, subjects.name as subject_name, courses.semester_number, courses.academic_year, courses.capacity, courses.size, courses.status
+ 				JOIN terms ON courses.term_code = terms.code
+ 				WHERE course_registrations.student_id = $1 AND courses.term_code = $2`
+	}
+	return `
  				FROM courses
  				JOIN users ON courses.teacher_id = users.id
  				JOIN subjects ON courses.subject_id = subjects.id
- 				WHERE courses.teacher_id = $1 AND courses.semester_number = $2 AND courses.academic_year = $3`
-	}
+ 				JOIN terms ON courses.term_code = terms.code
+ 				WHERE courses.teacher_id = $1 AND courses.term_code = $2`
+}
+
+func (c *courseRepo) GetCoursesByUserId(ctx context.Context, userId string, role string, termCode string, tx *sqlx.Tx) ([]model.Course, error) {
+	query := userCourseColumns + userCoursesFrom(role)
 
 	var err error
 	var rows *sqlx.Rows
 	if tx != nil {
-		rows, err = tx.QueryxContext(ctx, query, userId, semester, academicYear)
+		rows, err = tx.QueryxContext(ctx, query, userId, termCode)
 	} else {
-		rows, err = c.db.QueryxContext(ctx, query, userId, semester, academicYear)
+		rows, err = c.db.QueryxContext(ctx, query, userId, termCode)
 	}
 	if err != nil {
 		log.Println("Course repo, ger course by user id err: ", err)
@@ -168,8 +193,61 @@ func (c *courseRepo) GetCoursesByUserId(ctx context.Context, userId string, role
 	return courses, nil
 }
 
+// GetCourseListByUserId returns up to params.Limit courses of the user following cursor, or the first ones when cursor is nil.
+func (c *courseRepo) GetCourseListByUserId(ctx context.Context, params dto.GetCoursesParams, role string, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Course, error) {
+	args := []interface{}{params.UserId, params.TermCode}
+	condition, orderBy, args := keysetClause("courses.id", "courses.id", false, cursor, args)
+	query := userCourseColumns + userCoursesFrom(role)
+	if condition != "" {
+		query += ` AND ` + condition
+	}
+	args = append(args, params.Limit)
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = c.db.QueryxContext(ctx, query, args...)
+	}
+	if err != nil {
+		log.Println("Course repo, get course list err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	courses := []model.Course{}
+	for rows.Next() {
+		var course model.Course
+		err = rows.StructScan(&course)
+		if err != nil {
+			log.Println("Course repo, get course list err: ", err)
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+	return courses, nil
+}
+
+func (c *courseRepo) CountCoursesByUserId(ctx context.Context, params dto.GetCoursesParams, role string, tx *sqlx.Tx) (int, error) {
+	query := `SELECT COUNT(*)` + userCoursesFrom(role)
+	var total int
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, params.UserId, params.TermCode)
+	} else {
+		err = c.db.GetContext(ctx, &total, query, params.UserId, params.TermCode)
+	}
+	if err != nil {
+		log.Println("Course repo, count courses err: ", err)
+		return 0, err
+	}
+	return total, nil
+}
+
 func (c *courseRepo) GetCourseSchedulesByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseSchedule, error) {
-	query := `SELECT id, course_id, room, start_time, end_time FROM course_schedules WHERE course_id = $1`
+	query := `SELECT id, course_id, room, day_of_week, start_time, end_time, start_date, end_date, timezone, exception_dates
+			FROM course_schedules WHERE course_id = $1 ORDER BY id`
 
 	var err error
 	var rows *sqlx.Rows
@@ -198,6 +276,284 @@ func (c *courseRepo) GetCourseSchedulesByCourseId(ctx context.Context, courseId
 	return schedules, nil
 }
 
+func (c *courseRepo) GetCourseSchedulesByStudentId(ctx context.Context, studentId string, termCode string, tx *sqlx.Tx) ([]model.CourseSchedule, error) {
+	query := `SELECT course_schedules.id, course_schedules.course_id, course_schedules.room, course_schedules.day_of_week, course_schedules.start_time, course_schedules.end_time,
+       			course_schedules.start_date, course_schedules.end_date, course_schedules.timezone, course_schedules.exception_dates
+			FROM course_schedules
+			JOIN course_registrations ON course_schedules.course_id = course_registrations.course_id
+			JOIN courses ON course_schedules.course_id = courses.id
+			WHERE course_registrations.student_id = $1 AND courses.term_code = $2`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, studentId, termCode)
+	} else {
+		rows, err = c.db.QueryxContext(ctx, query, studentId, termCode)
+	}
+	if err != nil {
+		log.Println("Course repo, get student course schedules err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var schedules []model.CourseSchedule
+	for rows.Next() {
+		var schedule model.CourseSchedule
+		err = rows.StructScan(&schedule)
+		if err != nil {
+			log.Println("Course repo, get student course schedules err: ", err)
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// LockCourseSchedules blocks concurrent schedule writes until tx ends, so overlap checks stay valid until the insert.
+func (c *courseRepo) LockCourseSchedules(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, `LOCK TABLE course_schedules IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		log.Println("Course repo, lock course schedules err: ", err)
+		return err
+	}
+	return nil
+}
+
+// GetLargestCourseByRoom returns the course with the highest capacity among those scheduled in room.
+func (c *courseRepo) GetLargestCourseByRoom(ctx context.Context, room string, tx *sqlx.Tx) (model.Course, error) {
+	query := `SELECT courses.id, courses.capacity
+			FROM courses
+			JOIN course_schedules ON course_schedules.course_id = courses.id
+			WHERE course_schedules.room = $1
+			ORDER BY courses.capacity DESC, courses.id LIMIT 1`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, room)
+	} else {
+		row = c.db.QueryRowxContext(ctx, query, room)
+	}
+	course := model.Course{}
+	err := row.StructScan(&course)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return course, &error2.ResourceNotFoundErr{Resource: "Course"}
+		}
+		log.Println("Course repo, get largest course of room err: ", err)
+		return course, err
+	}
+	return course, nil
+}
+
+func (c *courseRepo) GetSchedulesByRoomInDateRange(ctx context.Context, room string, startDate string, endDate string, tx *sqlx.Tx) ([]model.CourseSchedule, error) {
+	query := `SELECT id, course_id, room, day_of_week, start_time, end_time, start_date, end_date, timezone, exception_dates
+			FROM course_schedules
+			WHERE room = $1 AND start_date <= $3 AND $2 <= end_date ORDER BY id`
+
+	var err error
+	var schedules []model.CourseSchedule
+	if tx != nil {
+		err = tx.SelectContext(ctx, &schedules, query, room, startDate, endDate)
+	} else {
+		err = c.db.SelectContext(ctx, &schedules, query, room, startDate, endDate)
+	}
+	if err != nil {
+		log.Println("Course repo, get overlapping room schedules err: ", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (c *courseRepo) GetSchedulesByTeacherIdInDateRange(ctx context.Context, teacherId string, startDate string, endDate string, tx *sqlx.Tx) ([]model.CourseSchedule, error) {
+	query := `SELECT course_schedules.id, course_schedules.course_id, course_schedules.room, course_schedules.day_of_week, course_schedules.start_time, course_schedules.end_time,
+       			course_schedules.start_date, course_schedules.end_date, course_schedules.timezone, course_schedules.exception_dates
+			FROM course_schedules
+			JOIN courses ON course_schedules.course_id = courses.id
+			WHERE courses.teacher_id = $1 AND course_schedules.start_date <= $3 AND $2 <= course_schedules.end_date
+			ORDER BY course_schedules.id`
+
+	var err error
+	var schedules []model.CourseSchedule
+	if tx != nil {
+		err = tx.SelectContext(ctx, &schedules, query, teacherId, startDate, endDate)
+	} else {
+		err = c.db.SelectContext(ctx, &schedules, query, teacherId, startDate, endDate)
+	}
+	if err != nil {
+		log.Println("Course repo, get overlapping teacher schedules err: ", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (c *courseRepo) GetSchedulesInDateRange(ctx context.Context, startDate string, endDate string, tx *sqlx.Tx) ([]model.CourseSchedule, error) {
+	query := `SELECT id, course_id, room, day_of_week, start_time, end_time, start_date, end_date, timezone, exception_dates
+			FROM course_schedules
+			WHERE start_date <= $2 AND $1 <= end_date ORDER BY id`
+
+	var err error
+	var schedules []model.CourseSchedule
+	if tx != nil {
+		err = tx.SelectContext(ctx, &schedules, query, startDate, endDate)
+	} else {
+		err = c.db.SelectContext(ctx, &schedules, query, startDate, endDate)
+	}
+	if err != nil {
+		log.Println("Course repo, get schedules in date range err: ", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (c *courseRepo) GetSchedulesByCourseIds(ctx context.Context, courseIds []string, tx *sqlx.Tx) ([]model.CourseSchedule, error) {
+	query := `SELECT id, course_id, room, day_of_week, start_time, end_time, start_date, end_date, timezone, exception_dates
+			FROM course_schedules
+			WHERE course_id = ANY($1) ORDER BY course_id, day_of_week, start_time`
+
+	var err error
+	var schedules []model.CourseSchedule
+	if tx != nil {
+		err = tx.SelectContext(ctx, &schedules, query, pq.Array(courseIds))
+	} else {
+		err = c.db.SelectContext(ctx, &schedules, query, pq.Array(courseIds))
+	}
+	if err != nil {
+		log.Println("Course repo, get schedules by course ids err: ", err)
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func searchCoursesConditions(params dto.SearchCoursesParams) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if params.TermCode != "" {
+		args = append(args, params.TermCode)
+		conditions = append(conditions, fmt.Sprintf("courses.term_code = $%d", len(args)))
+	}
+	if params.SubjectId != "" {
+		args = append(args, params.SubjectId)
+		conditions = append(conditions, fmt.Sprintf("courses.subject_id = $%d", len(args)))
+	}
+	if params.Major != "" {
+		args = append(args, params.Major)
+		conditions = append(conditions, fmt.Sprintf("subjects.major = $%d", len(args)))
+	}
+	if params.TeacherId != "" {
+		args = append(args, params.TeacherId)
+		conditions = append(conditions, fmt.Sprintf("courses.teacher_id = $%d", len(args)))
+	}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("courses.status = $%d", len(args)))
+	}
+	if params.HasFreeSeats {
+		conditions = append(conditions, "courses.size < courses.capacity")
+	}
+	if params.Credits > 0 {
+		args = append(args, params.Credits)
+		conditions = append(conditions, fmt.Sprintf("subjects.number_of_credit = $%d", len(args)))
+	}
+	if params.DayOfWeek > 0 || params.From != "" || params.To != "" {
+		scheduleConditions := []string{"course_schedules.course_id = courses.id"}
+		if params.DayOfWeek > 0 {
+			args = append(args, params.DayOfWeek)
+			scheduleConditions = append(scheduleConditions, fmt.Sprintf("course_schedules.day_of_week = $%d", len(args)))
+		}
+		if params.From != "" {
+			args = append(args, params.From)
+			scheduleConditions = append(scheduleConditions, fmt.Sprintf("course_schedules.start_time >= $%d", len(args)))
+		}
+		if params.To != "" {
+			args = append(args, params.To)
+			scheduleConditions = append(scheduleConditions, fmt.Sprintf("course_schedules.end_time <= $%d", len(args)))
+		}
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM course_schedules WHERE `+strings.Join(scheduleConditions, " AND ")+`)`)
+	}
+	return conditions, args
+}
+
+const searchCoursesFrom = `
+			FROM courses
+			JOIN users ON courses.teacher_id = users.id
+			JOIN subjects ON courses.subject_id = subjects.id
+			JOIN terms ON courses.term_code = terms.code`
+
+// SearchCourses returns up to params.Limit catalog courses following cursor, or the first ones when cursor is nil.
+func (c *courseRepo) SearchCourses(ctx context.Context, params dto.SearchCoursesParams, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.CatalogCourse, error) {
+	conditions, args := searchCoursesConditions(params)
+	condition, orderBy, args := keysetClause("courses.id", "courses.id", false, cursor, args)
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	args = append(args, params.Limit)
+	query := `SELECT courses.id, courses.teacher_id, users.name as teacher_name, courses.subject_id, subjects.name as subject_name, subjects.number_of_credit, subjects.major, courses.term_code, terms.semester_number, terms.academic_year, courses.capacity, courses.size, courses.status, courses.waitlist_enabled` +
+		searchCoursesFrom + whereClause(conditions) + fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = c.db.QueryxContext(ctx, query, args...)
+	}
+	if err != nil {
+		log.Println("Course repo, search courses err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	courses := []model.CatalogCourse{}
+	for rows.Next() {
+		var course model.CatalogCourse
+		err = rows.StructScan(&course)
+		if err != nil {
+			log.Println("Course repo, search courses err: ", err)
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+	return courses, nil
+}
+
+func (c *courseRepo) CountSearchCourses(ctx context.Context, params dto.SearchCoursesParams, tx *sqlx.Tx) (int, error) {
+	conditions, args := searchCoursesConditions(params)
+	query := `SELECT COUNT(*)` + searchCoursesFrom + whereClause(conditions)
+	var total int
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, args...)
+	} else {
+		err = c.db.GetContext(ctx, &total, query, args...)
+	}
+	if err != nil {
+		log.Println("Course repo, count search courses err: ", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+func (c *courseRepo) GetStudentsByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.Student, error) {
+	query := `SELECT users.id, users.name, users.email, students.major, students.school_year
+			FROM course_registrations
+			JOIN students ON course_registrations.student_id = students.id
+			JOIN users ON students.id = users.id
+			WHERE course_registrations.course_id = $1
+			ORDER BY users.name, users.id`
+
+	var err error
+	students := []model.Student{}
+	if tx != nil {
+		err = tx.SelectContext(ctx, &students, query, courseId)
+	} else {
+		err = c.db.SelectContext(ctx, &students, query, courseId)
+	}
+	if err != nil {
+		log.Println("Course repo, get students by course id err: ", err)
+		return nil, err
+	}
+	return students, nil
+}
+
 func (c *courseRepo) DeleteCourseScheduleById(ctx context.Context, id string, tx *sqlx.Tx) error {
 	query := `DELETE FROM course_schedules WHERE id = $1`
 
@@ -215,8 +571,8 @@ func (c *courseRepo) DeleteCourseScheduleById(ctx context.Context, id string, tx
 }
 
 func (c *courseRepo) CreateCourse(ctx context.Context, course model.Course, tx *sqlx.Tx) error {
-	query := `INSERT INTO courses(id, teacher_id, subject_id, semester_number, academic_year,capacity,size, status) 
-			VALUES (:id, :teacher_id, :subject_id, :semester_number, :academic_year, :capacity, :size, :status)`
+	query := `INSERT INTO courses(id, teacher_id, subject_id, term_code, capacity, size, status, waitlist_enabled) 
+			VALUES (:id, :teacher_id, :subject_id, :term_code, :capacity, :size, :status, COALESCE(:waitlist_enabled, FALSE))`
 
 	var err error
 	if tx != nil {
@@ -232,10 +588,11 @@ func (c *courseRepo) CreateCourse(ctx context.Context, course model.Course, tx *
 }
 
 func (c *courseRepo) GetCourseById(ctx context.Context, id string, tx *sqlx.Tx) (model.Course, error) {
-	query := `SELECT courses.id, users.name as teacher_name, subjects.name as subject_name, courses.semester_number, courses.academic_year, courses.capacity, courses.size, courses.status
+	query := `SELECT courses.id, courses.teacher_id, users.name as teacher_name, courses.subject_id, subjects.name as subject_name, courses.term_code, terms.semester_number, terms.academic_year, courses.capacity, courses.size, courses.status, courses.waitlist_enabled
 			FROM courses
 			JOIN users ON courses.teacher_id = users.id
 			JOIN subjects ON courses.subject_id = subjects.id
+			JOIN terms ON courses.term_code = terms.code
 			WHERE courses.id = $1`
 
 	var row *sqlx.Row
@@ -332,8 +689,30 @@ func (c *courseRepo) DeleteCourseRegistration(ctx context.Context, courseId stri
 	return nil
 }
 
+func (c *courseRepo) GetCourseRegistration(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) (model.CourseRegistration, error) {
+	query := `SELECT id, course_id, student_id FROM course_registrations WHERE course_id = $1 AND student_id = $2`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, courseId, studentId)
+	} else {
+		row = c.db.QueryRowxContext(ctx, query, courseId, studentId)
+	}
+	var registration model.CourseRegistration
+	err := row.StructScan(&registration)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return registration, &error2.ResourceNotFoundErr{Resource: "course registration"}
+		}
+		log.Println("Course repo, get course registration err:", err)
+		return registration, err
+	}
+	return registration, nil
+}
+
 func (c *courseRepo) AddCourseSchedule(ctx context.Context, schedule model.CourseSchedule, tx *sqlx.Tx) error {
-	query := `INSERT INTO course_schedules(course_id, room, start_time, end_time) VALUES (:course_id, :room, :start_time, :end_time)`
+	query := `INSERT INTO course_schedules(course_id, room, day_of_week, start_time, end_time, start_date, end_date, timezone, exception_dates)
+			VALUES (:course_id, :room, :day_of_week, :start_time, :end_time, :start_date, :end_date, :timezone, :exception_dates)`
 	var err error
 	if tx != nil {
 		_, err = tx.NamedExecContext(ctx, query, schedule)
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"SchoolManagement/dto"
+	"SchoolManagement/model"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"log"
+)
+
+type CourseStatusRepo interface {
+	InsertStatusChange(ctx context.Context, change model.CourseStatusChange, tx *sqlx.Tx) error
+	GetStatusChanges(ctx context.Context, actor string, params dto.PaginationParams, tx *sqlx.Tx) ([]model.CourseStatusChange, error)
+	GetStatusChangesByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseStatusChange, error)
+	GetUnfinishedCourseCalendars(ctx context.Context, tx *sqlx.Tx) ([]model.CourseCalendar, error)
+}
+
+type courseStatusRepo struct {
+	db *sqlx.DB
+}
+
+func (c *courseStatusRepo) InsertStatusChange(ctx context.Context, change model.CourseStatusChange, tx *sqlx.Tx) error {
+	query := `INSERT INTO course_status_history(course_id, from_status, to_status, actor, reason, created_at)
+			VALUES (:course_id, :from_status, :to_status, :actor, :reason, :created_at)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, change)
+	} else {
+		_, err = c.db.NamedExecContext(ctx, query, change)
+	}
+	if err != nil {
+		log.Println("Course status repo, insert status change err: ", err)
+		return err
+	}
+	return nil
+}
+
+// GetStatusChanges returns the newest changes first, limited to one actor unless actor is empty.
+func (c *courseStatusRepo) GetStatusChanges(ctx context.Context, actor string, params dto.PaginationParams, tx *sqlx.Tx) ([]model.CourseStatusChange, error) {
+	query := `SELECT id, course_id, from_status, to_status, actor, reason, created_at FROM course_status_history`
+	var args []interface{}
+	if actor != "" {
+		args = append(args, actor)
+		query += ` WHERE actor = $1`
+	}
+	args = append(args, params.Limit, params.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = c.db.QueryxContext(ctx, query, args...)
+	}
+	if err != nil {
+		log.Println("Course status repo, get status changes err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var changes []model.CourseStatusChange
+	for rows.Next() {
+		var change model.CourseStatusChange
+		err = rows.StructScan(&change)
+		if err != nil {
+			log.Println("Course status repo, get status changes err: ", err)
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (c *courseStatusRepo) GetStatusChangesByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseStatusChange, error) {
+	query := `SELECT id, course_id, from_status, to_status, actor, reason, created_at
+			FROM course_status_history WHERE course_id = $1 ORDER BY created_at, id`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, courseId)
+	} else {
+		rows, err = c.db.QueryxContext(ctx, query, courseId)
+	}
+	if err != nil {
+		log.Println("Course status repo, get course history err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var changes []model.CourseStatusChange
+	for rows.Next() {
+		var change model.CourseStatusChange
+		err = rows.StructScan(&change)
+		if err != nil {
+			log.Println("Course status repo, get course history err: ", err)
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (c *courseStatusRepo) GetUnfinishedCourseCalendars(ctx context.Context, tx *sqlx.Tx) ([]model.CourseCalendar, error) {
+	query := `SELECT courses.id AS course_id, courses.status, courses.term_code, terms.registration_open, terms.start_date, terms.end_date
+			FROM courses
+			JOIN terms ON courses.term_code = terms.code
+			WHERE courses.status <> $1
+			ORDER BY courses.id`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, model.CourseStatusComplete)
+	} else {
+		rows, err = c.db.QueryxContext(ctx, query, model.CourseStatusComplete)
+	}
+	if err != nil {
+		log.Println("Course status repo, get course calendars err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var calendars []model.CourseCalendar
+	for rows.Next() {
+		var calendar model.CourseCalendar
+		err = rows.StructScan(&calendar)
+		if err != nil {
+			log.Println("Course status repo, get course calendars err: ", err)
+			return nil, err
+		}
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
+
+func NewCourseStatusRepo(db *sqlx.DB) CourseStatusRepo {
+	return &courseStatusRepo{db: db}
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"SchoolManagement/dto"
+	"fmt"
+	"strings"
+)
+
+// keysetClause returns the condition selecting the rows past cursor and the ORDER BY reading them in the
+// cursor's direction. The condition is empty on the first page.
+func keysetClause(sortColumn string, idColumn string, desc bool, cursor *dto.Cursor, args []interface{}) (string, string, []interface{}) {
+	if cursor != nil && cursor.Backward {
+		desc = !desc
+	}
+	operator, direction := ">", "ASC"
+	if desc {
+		operator, direction = "<", "DESC"
+	}
+	orderBy := fmt.Sprintf("%s %s", idColumn, direction)
+	if sortColumn != idColumn {
+		orderBy = fmt.Sprintf("%s %s, %s", sortColumn, direction, orderBy)
+	}
+	if cursor == nil {
+		return "", orderBy, args
+	}
+	if sortColumn == idColumn {
+		args = append(args, cursor.Id)
+		return fmt.Sprintf("%s %s $%d", idColumn, operator, len(args)), orderBy, args
+	}
+	args = append(args, cursor.Value, cursor.Id)
+	return fmt.Sprintf("(%s, %s) %s ($%d, $%d)", sortColumn, idColumn, operator, len(args)-1, len(args)), orderBy, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND ")
+}
+
+// containsPattern is an ILIKE pattern matching value anywhere, with the wildcards and the escape character in value
+// taken literally.
+func containsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"SchoolManagement/dto"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+	"reflect"
+	"strings"
+)
+
+type RoomRepo interface {
+	InsertRoom(ctx context.Context, room model.Room, tx *sqlx.Tx) error
+	UpdateRoom(ctx context.Context, room model.Room, tx *sqlx.Tx) error
+	DeleteRoomById(ctx context.Context, id string, tx *sqlx.Tx) error
+	GetRoomById(ctx context.Context, id string, tx *sqlx.Tx) (model.Room, error)
+	GetSmallestRoomByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) (model.Room, error)
+	GetRoomList(ctx context.Context, params dto.GetRoomsParams, tx *sqlx.Tx) ([]model.Room, error)
+}
+
+type roomRepo struct {
+	db *sqlx.DB
+}
+
+func (r *roomRepo) InsertRoom(ctx context.Context, room model.Room, tx *sqlx.Tx) error {
+	query := `INSERT INTO rooms(id, building, capacity, features) VALUES (:id, :building, :capacity, :features)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, room)
+	} else {
+		_, err = r.db.NamedExecContext(ctx, query, room)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: "room already exists"}
+		}
+		log.Println("Room repo, insert room err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *roomRepo) UpdateRoom(ctx context.Context, room model.Room, tx *sqlx.Tx) error {
+	var updateFields []string
+	t := reflect.TypeOf(room)
+	v := reflect.ValueOf(room)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+		if !value.IsZero() {
+			updateFields = append(updateFields, field.Tag.Get("db")+" = :"+field.Tag.Get("db"))
+		}
+	}
+	if len(updateFields) == 0 {
+		return nil
+	}
+	query := `UPDATE rooms SET ` + strings.Join(updateFields, ",") + ` WHERE id = :id`
+
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, query, room)
+	} else {
+		result, err = r.db.NamedExecContext(ctx, query, room)
+	}
+	if err != nil {
+		log.Println("Room repo, update room err: ", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Room repo, update room err: ", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		return &error2.ResourceNotFoundErr{Resource: "Room"}
+	}
+	return nil
+}
+
+func (r *roomRepo) DeleteRoomById(ctx context.Context, id string, tx *sqlx.Tx) error {
+	query := `DELETE FROM rooms WHERE id = $1`
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, id)
+	} else {
+		result, err = r.db.ExecContext(ctx, query, id)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return &error2.InvalidInputErr{Message: "room is still used by course schedules"}
+		}
+		log.Println("Room repo, delete room err: ", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Room repo, delete room err: ", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		return &error2.ResourceNotFoundErr{Resource: "Room"}
+	}
+	return nil
+}
+
+func (r *roomRepo) GetRoomById(ctx context.Context, id string, tx *sqlx.Tx) (model.Room, error) {
+	query := `SELECT id, building, capacity, features FROM rooms WHERE id = $1`
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, id)
+	} else {
+		row = r.db.QueryRowxContext(ctx, query, id)
+	}
+	var room model.Room
+	err := row.StructScan(&room)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return room, &error2.ResourceNotFoundErr{Resource: "Room"}
+		}
+		log.Println("Room repo, get room err: ", err)
+		return room, err
+	}
+	return room, nil
+}
+
+// GetSmallestRoomByCourseId returns the smallest room the course is scheduled in.
+func (r *roomRepo) GetSmallestRoomByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) (model.Room, error) {
+	query := `SELECT rooms.id, rooms.building, rooms.capacity, rooms.features
+			FROM rooms
+			JOIN course_schedules ON course_schedules.room = rooms.id
+			WHERE course_schedules.course_id = $1
+			ORDER BY rooms.capacity, rooms.id LIMIT 1`
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, courseId)
+	} else {
+		row = r.db.QueryRowxContext(ctx, query, courseId)
+	}
+	var room model.Room
+	err := row.StructScan(&room)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return room, &error2.ResourceNotFoundErr{Resource: "Room"}
+		}
+		log.Println("Room repo, get smallest room of course err: ", err)
+		return room, err
+	}
+	return room, nil
+}
+
+// GetRoomList returns every matching room when params.Limit is 0.
+func (r *roomRepo) GetRoomList(ctx context.Context, params dto.GetRoomsParams, tx *sqlx.Tx) ([]model.Room, error) {
+	var conditions []string
+	var args []interface{}
+	if params.Building != "" {
+		args = append(args, params.Building)
+		conditions = append(conditions, fmt.Sprintf("building = $%d", len(args)))
+	}
+	if params.MinCapacity > 0 {
+		args = append(args, params.MinCapacity)
+		conditions = append(conditions, fmt.Sprintf("capacity >= $%d", len(args)))
+	}
+	if len(params.Features) > 0 {
+		args = append(args, pq.Array(params.Features))
+		conditions = append(conditions, fmt.Sprintf("features @> $%d", len(args)))
+	}
+	query := `SELECT id, building, capacity, features FROM rooms`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY id`
+	if params.Limit > 0 {
+		args = append(args, params.Limit, params.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	var rows *sqlx.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = r.db.QueryxContext(ctx, query, args...)
+	}
+	if err != nil {
+		log.Println("Room repo, get rooms err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var rooms []model.Room
+	for rows.Next() {
+		var room model.Room
+		err = rows.StructScan(&room)
+		if err != nil {
+			log.Println("Room repo, get rooms err: ", err)
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	if len(rooms) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "Room"}
+	}
+	return rooms, nil
+}
+
+func NewRoomRepo(db *sqlx.DB) RoomRepo {
+	return &roomRepo{db: db}
+}
@@ -0,0 +1,293 @@
+package postgres
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+)
+
+type ScoreRepo interface {
+	GetComponentScoresByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.ComponentScore, error)
+	ReplaceComponentScores(ctx context.Context, courseId string, components []model.ComponentScore, tx *sqlx.Tx) error
+	UpsertStudentScore(ctx context.Context, score model.StudentScore, tx *sqlx.Tx) error
+	GetStudentScoresByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.StudentScore, error)
+	GetCourseGrades(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseRegistration, error)
+	UpdateCourseGrade(ctx context.Context, registration model.CourseRegistration, tx *sqlx.Tx) error
+	IsCourseGradeFinalized(ctx context.Context, courseId string, tx *sqlx.Tx) (bool, error)
+	ReopenCourseGrades(ctx context.Context, courseId string, tx *sqlx.Tx) error
+	GetFinalizedGradesByStudentId(ctx context.Context, studentId string, tx *sqlx.Tx) ([]model.CourseGrade, error)
+	GetPassedSubjectIdsByStudentId(ctx context.Context, studentId string, tx *sqlx.Tx) ([]string, error)
+}
+
+type scoreRepo struct {
+	db *sqlx.DB
+}
+
+func (s *scoreRepo) GetComponentScoresByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.ComponentScore, error) {
+	query := `SELECT id, course_id, name, score_weight FROM component_scores WHERE course_id = $1 ORDER BY id`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, courseId)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query, courseId)
+	}
+	if err != nil {
+		log.Println("Score repo, get component scores err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var components []model.ComponentScore
+	for rows.Next() {
+		var component model.ComponentScore
+		err = rows.StructScan(&component)
+		if err != nil {
+			log.Println("Score repo, get component scores err: ", err)
+			return nil, err
+		}
+		components = append(components, component)
+	}
+	if len(components) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "component scores"}
+	}
+	return components, nil
+}
+
+// ReplaceComponentScores makes components the whole set of components of the course in one statement: components
+// with an id are updated, the others inserted and the missing ones deleted along with their student scores. The
+// name constraint is only checked at commit, so that components can swap names.
+func (s *scoreRepo) ReplaceComponentScores(ctx context.Context, courseId string, components []model.ComponentScore, tx *sqlx.Tx) error {
+	query := `WITH input AS (
+				SELECT * FROM unnest($2::INT[], $3::TEXT[], $4::DOUBLE PRECISION[]) AS t(id, name, score_weight)
+			), deleted AS (
+				DELETE FROM component_scores WHERE course_id = $1 AND id NOT IN (SELECT id FROM input)
+			), updated AS (
+				UPDATE component_scores SET name = input.name, score_weight = input.score_weight
+				FROM input WHERE component_scores.id = input.id AND component_scores.course_id = $1
+			)
+			INSERT INTO component_scores(course_id, name, score_weight)
+			SELECT $1, name, score_weight FROM input WHERE id = 0`
+
+	ids := make([]int64, len(components))
+	names := make([]string, len(components))
+	weights := make([]float64, len(components))
+	for i, component := range components {
+		ids[i] = int64(component.Id)
+		names[i] = component.Name
+		weights[i] = component.ScoreWeight
+	}
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, courseId, pq.Array(ids), pq.Array(names), pq.Array(weights))
+	} else {
+		_, err = s.db.ExecContext(ctx, query, courseId, pq.Array(ids), pq.Array(names), pq.Array(weights))
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: "component name already exists"}
+		}
+		log.Println("Score repo, replace component scores err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *scoreRepo) UpsertStudentScore(ctx context.Context, score model.StudentScore, tx *sqlx.Tx) error {
+	query := `INSERT INTO student_scores(component_id, student_id, score) VALUES (:component_id, :student_id, :score)
+			ON CONFLICT (component_id, student_id) DO UPDATE SET score = EXCLUDED.score`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, score)
+	} else {
+		_, err = s.db.NamedExecContext(ctx, query, score)
+	}
+	if err != nil {
+		log.Println("Score repo, upsert student score err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *scoreRepo) GetStudentScoresByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.StudentScore, error) {
+	query := `SELECT student_scores.id, student_scores.component_id, component_scores.name as component_name, student_scores.student_id, student_scores.score
+			FROM student_scores
+			JOIN component_scores ON student_scores.component_id = component_scores.id
+			WHERE component_scores.course_id = $1
+			ORDER BY student_scores.student_id, student_scores.component_id`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, courseId)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query, courseId)
+	}
+	if err != nil {
+		log.Println("Score repo, get student scores err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var scores []model.StudentScore
+	for rows.Next() {
+		var score model.StudentScore
+		err = rows.StructScan(&score)
+		if err != nil {
+			log.Println("Score repo, get student scores err: ", err)
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+	if len(scores) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "student scores"}
+	}
+	return scores, nil
+}
+
+func (s *scoreRepo) GetCourseGrades(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseRegistration, error) {
+	query := `SELECT id, course_id, student_id, COALESCE(final_score, 0) as final_score, COALESCE(letter_grade, '') as letter_grade, COALESCE(grade_point, 0) as grade_point, grade_finalized
+			FROM course_registrations WHERE course_id = $1 ORDER BY student_id`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, courseId)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query, courseId)
+	}
+	if err != nil {
+		log.Println("Score repo, get course grades err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var registrations []model.CourseRegistration
+	for rows.Next() {
+		var registration model.CourseRegistration
+		err = rows.StructScan(&registration)
+		if err != nil {
+			log.Println("Score repo, get course grades err: ", err)
+			return nil, err
+		}
+		registrations = append(registrations, registration)
+	}
+	if len(registrations) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "course registrations"}
+	}
+	return registrations, nil
+}
+
+func (s *scoreRepo) UpdateCourseGrade(ctx context.Context, registration model.CourseRegistration, tx *sqlx.Tx) error {
+	query := `UPDATE course_registrations SET final_score = :final_score, letter_grade = :letter_grade, grade_point = :grade_point, grade_finalized = :grade_finalized
+			WHERE course_id = :course_id AND student_id = :student_id`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, registration)
+	} else {
+		_, err = s.db.NamedExecContext(ctx, query, registration)
+	}
+	if err != nil {
+		log.Println("Score repo, update course grade err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *scoreRepo) IsCourseGradeFinalized(ctx context.Context, courseId string, tx *sqlx.Tx) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM course_registrations WHERE course_id = $1 AND grade_finalized)`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, courseId)
+	} else {
+		row = s.db.QueryRowxContext(ctx, query, courseId)
+	}
+	var finalized bool
+	err := row.Scan(&finalized)
+	if err != nil {
+		log.Println("Score repo, check course grade finalized err: ", err)
+		return false, err
+	}
+	return finalized, nil
+}
+
+func (s *scoreRepo) ReopenCourseGrades(ctx context.Context, courseId string, tx *sqlx.Tx) error {
+	query := `UPDATE course_registrations SET grade_finalized = FALSE WHERE course_id = $1`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, courseId)
+	} else {
+		_, err = s.db.ExecContext(ctx, query, courseId)
+	}
+	if err != nil {
+		log.Println("Score repo, reopen course grades err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *scoreRepo) GetFinalizedGradesByStudentId(ctx context.Context, studentId string, tx *sqlx.Tx) ([]model.CourseGrade, error) {
+	query := `SELECT courses.id as course_id, subjects.id as subject_id, subjects.name as subject_name, subjects.number_of_credit, courses.term_code, terms.semester_number, terms.academic_year,
+       			course_registrations.final_score, course_registrations.letter_grade, course_registrations.grade_point
+			FROM course_registrations
+			JOIN courses ON course_registrations.course_id = courses.id
+			JOIN subjects ON courses.subject_id = subjects.id
+			JOIN terms ON courses.term_code = terms.code
+			WHERE course_registrations.student_id = $1 AND courses.status = $2 AND course_registrations.grade_finalized
+			ORDER BY terms.start_date, terms.code, subjects.id`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, studentId, model.CourseStatusComplete)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query, studentId, model.CourseStatusComplete)
+	}
+	if err != nil {
+		log.Println("Score repo, get finalized grades err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var grades []model.CourseGrade
+	for rows.Next() {
+		var grade model.CourseGrade
+		err = rows.StructScan(&grade)
+		if err != nil {
+			log.Println("Score repo, get finalized grades err: ", err)
+			return nil, err
+		}
+		grades = append(grades, grade)
+	}
+	return grades, nil
+}
+
+func (s *scoreRepo) GetPassedSubjectIdsByStudentId(ctx context.Context, studentId string, tx *sqlx.Tx) ([]string, error) {
+	query := `SELECT DISTINCT courses.subject_id
+			FROM course_registrations
+			JOIN courses ON course_registrations.course_id = courses.id
+			WHERE course_registrations.student_id = $1 AND course_registrations.grade_finalized AND course_registrations.grade_point > 0`
+
+	var err error
+	var subjectIds []string
+	if tx != nil {
+		err = tx.SelectContext(ctx, &subjectIds, query, studentId)
+	} else {
+		err = s.db.SelectContext(ctx, &subjectIds, query, studentId)
+	}
+	if err != nil {
+		log.Println("Score repo, get passed subjects err: ", err)
+		return nil, err
+	}
+	return subjectIds, nil
+}
+
+func NewScoreRepo(db *sqlx.DB) ScoreRepo {
+	return &scoreRepo{db: db}
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"SchoolManagement/model"
+	"context"
+	"github.com/jmoiron/sqlx"
+	"log"
+)
+
+// signingKeysLockId is the advisory lock serialising key rotations between server instances.
+const signingKeysLockId = 7301
+
+type SigningKeyRepo interface {
+	LockSigningKeys(ctx context.Context, tx *sqlx.Tx) error
+	GetSigningKeys(ctx context.Context, now int64, tx *sqlx.Tx) ([]model.SigningKey, error)
+	InsertSigningKey(ctx context.Context, key model.SigningKey, tx *sqlx.Tx) error
+	ExpireSigningKeys(ctx context.Context, expiresAt int64, tx *sqlx.Tx) error
+	DeleteExpiredSigningKeys(ctx context.Context, now int64, tx *sqlx.Tx) error
+}
+
+type signingKeyRepo struct {
+	db *sqlx.DB
+}
+
+// LockSigningKeys holds the rotation lock until tx ends, so it needs a transaction.
+func (s *signingKeyRepo) LockSigningKeys(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, signingKeysLockId)
+	if err != nil {
+		log.Println("Signing key repo, lock signing keys err: ", err)
+		return err
+	}
+	return nil
+}
+
+// GetSigningKeys returns the keys that have not expired at now, oldest activation first.
+func (s *signingKeyRepo) GetSigningKeys(ctx context.Context, now int64, tx *sqlx.Tx) ([]model.SigningKey, error) {
+	query := `SELECT kid, algorithm, private_key, activates_at, expires_at FROM jwt_signing_keys
+			WHERE expires_at = 0 OR expires_at > $1 ORDER BY activates_at`
+	keys := []model.SigningKey{}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &keys, query, now)
+	} else {
+		err = s.db.SelectContext(ctx, &keys, query, now)
+	}
+	if err != nil {
+		log.Println("Signing key repo, get signing keys err: ", err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *signingKeyRepo) InsertSigningKey(ctx context.Context, key model.SigningKey, tx *sqlx.Tx) error {
+	query := `INSERT INTO jwt_signing_keys(kid, algorithm, private_key, activates_at, expires_at)
+			VALUES (:kid, :algorithm, :private_key, :activates_at, :expires_at)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, key)
+	} else {
+		_, err = s.db.NamedExecContext(ctx, query, key)
+	}
+	if err != nil {
+		log.Println("Signing key repo, insert signing key err: ", err)
+		return err
+	}
+	return nil
+}
+
+// ExpireSigningKeys schedules the expiry of every key that has none yet.
+func (s *signingKeyRepo) ExpireSigningKeys(ctx context.Context, expiresAt int64, tx *sqlx.Tx) error {
+	query := `UPDATE jwt_signing_keys SET expires_at = $1 WHERE expires_at = 0`
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, expiresAt)
+	} else {
+		_, err = s.db.ExecContext(ctx, query, expiresAt)
+	}
+	if err != nil {
+		log.Println("Signing key repo, expire signing keys err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *signingKeyRepo) DeleteExpiredSigningKeys(ctx context.Context, now int64, tx *sqlx.Tx) error {
+	query := `DELETE FROM jwt_signing_keys WHERE expires_at <> 0 AND expires_at <= $1`
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, now)
+	} else {
+		_, err = s.db.ExecContext(ctx, query, now)
+	}
+	if err != nil {
+		log.Println("Signing key repo, delete expired signing keys err: ", err)
+		return err
+	}
+	return nil
+}
+
+func NewSigningKeyRepo(db *sqlx.DB) SigningKeyRepo {
+	return &signingKeyRepo{db: db}
+}
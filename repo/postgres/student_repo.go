@@ -1,11 +1,13 @@
 package postgres
 
 import (
+	"SchoolManagement/dto"
 	error2 "SchoolManagement/error"
 	"SchoolManagement/model"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"log"
@@ -18,6 +20,8 @@ type StudentRepo interface {
 	UpdateStudent(ctx context.Context, student model.Student, tx *sqlx.Tx) error
 	DeleteStudentById(ctx context.Context, id string, tx *sqlx.Tx) error
 	InsertStudent(ctx context.Context, student model.Student, tx *sqlx.Tx) error
+	GetStudentList(ctx context.Context, params dto.GetStudentsParams, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Student, error)
+	CountStudents(ctx context.Context, params dto.GetStudentsParams, tx *sqlx.Tx) (int, error)
 }
 
 type studentRepo struct {
@@ -118,6 +122,96 @@ func (s *studentRepo) InsertStudent(ctx context.Context, student model.Student,
 	return nil
 }
 
+// studentSortColumns replaces NULL by the lowest value, a NULL would never pass the keyset condition. Cursors on
+// date_of_birth carry -infinity for students without one.
+var studentSortColumns = map[string]string{
+	"id":            "users.id",
+	"name":          "name",
+	"date_of_birth": "COALESCE(date_of_birth, '-infinity')",
+	"school_year":   "COALESCE(school_year, '')",
+	"major":         "COALESCE(major, '')",
+}
+
+func studentListConditions(params dto.GetStudentsParams) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if params.Major != "" {
+		args = append(args, params.Major)
+		conditions = append(conditions, fmt.Sprintf("major = $%d", len(args)))
+	}
+	if params.SchoolYear != "" {
+		args = append(args, params.SchoolYear)
+		conditions = append(conditions, fmt.Sprintf("school_year = $%d", len(args)))
+	}
+	if params.Gender != "" {
+		args = append(args, params.Gender)
+		conditions = append(conditions, fmt.Sprintf("gender = $%d", len(args)))
+	}
+	if params.Name != "" {
+		args = append(args, containsPattern(params.Name))
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// GetStudentList returns up to params.Limit matching students following cursor, or the first ones when cursor is nil.
+func (s *studentRepo) GetStudentList(ctx context.Context, params dto.GetStudentsParams, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Student, error) {
+	conditions, args := studentListConditions(params)
+	sortColumn, ok := studentSortColumns[params.SortBy]
+	if !ok {
+		sortColumn = "users.id"
+	}
+	condition, orderBy, args := keysetClause(sortColumn, "users.id", params.SortOrder == "desc", cursor, args)
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	args = append(args, params.Limit)
+	query := `SELECT users.id, name, date_of_birth, gender, email, identity_number, phone_number, address, role, school_year, major
+			FROM users JOIN students s ON users.id = s.id` + whereClause(conditions) +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
+	var rows *sqlx.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query, args...)
+	}
+	if err != nil {
+		log.Println("Student repo, get students err:", err)
+		return nil, err
+	}
+	defer rows.Close()
+	students := []model.Student{}
+	for rows.Next() {
+		var student model.Student
+		err = rows.StructScan(&student)
+		if err != nil {
+			log.Println("Student repo, get students err:", err)
+			return nil, err
+		}
+		students = append(students, student)
+	}
+	return students, nil
+}
+
+func (s *studentRepo) CountStudents(ctx context.Context, params dto.GetStudentsParams, tx *sqlx.Tx) (int, error) {
+	conditions, args := studentListConditions(params)
+	query := `SELECT COUNT(*) FROM users JOIN students s ON users.id = s.id` + whereClause(conditions)
+	var total int
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, args...)
+	} else {
+		err = s.db.GetContext(ctx, &total, query, args...)
+	}
+	if err != nil {
+		log.Println("Student repo, count students err:", err)
+		return 0, err
+	}
+	return total, nil
+}
+
 func NewStudentRepo(db *sqlx.DB) StudentRepo {
 	return &studentRepo{db: db}
 }
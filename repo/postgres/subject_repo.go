@@ -7,7 +7,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"log"
 	"reflect"
 	"strings"
@@ -18,7 +20,14 @@ type SubjectRepo interface {
 	UpdateSubject(ctx context.Context, subject model.Subject, tx *sqlx.Tx) error
 	DeleteSubjectById(ctx context.Context, id string, tx *sqlx.Tx) error
 	GetSubjectById(ctx context.Context, id string, tx *sqlx.Tx) (model.Subject, error)
-	GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO, tx *sqlx.Tx) ([]model.Subject, error)
+	GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Subject, error)
+	CountSubjects(ctx context.Context, params dto.GetSubjectsParamDTO, tx *sqlx.Tx) (int, error)
+	InsertSubjectPrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite, tx *sqlx.Tx) error
+	UpdateSubjectPrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite, tx *sqlx.Tx) error
+	DeleteSubjectPrerequisite(ctx context.Context, subjectId string, prerequisiteId string, tx *sqlx.Tx) error
+	GetPrerequisitesBySubjectId(ctx context.Context, subjectId string, tx *sqlx.Tx) ([]model.SubjectPrerequisite, error)
+	GetAllSubjectPrerequisites(ctx context.Context, tx *sqlx.Tx) ([]model.SubjectPrerequisite, error)
+	LockSubjectPrerequisites(ctx context.Context, tx *sqlx.Tx) error
 }
 
 type subjectRepo struct {
@@ -106,29 +115,39 @@ func (s *subjectRepo) GetSubjectById(ctx context.Context, id string, tx *sqlx.Tx
 	return subject, nil
 }
 
-func (s *subjectRepo) GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO, tx *sqlx.Tx) ([]model.Subject, error) {
+func subjectListConditions(params dto.GetSubjectsParamDTO) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if params.Major != "" {
+		args = append(args, params.Major)
+		conditions = append(conditions, fmt.Sprintf("major = $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// GetSubjectList returns up to params.Limit subjects following cursor, or the first ones when cursor is nil.
+func (s *subjectRepo) GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Subject, error) {
+	conditions, args := subjectListConditions(params)
+	condition, orderBy, args := keysetClause("id", "id", false, cursor, args)
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	args = append(args, params.Limit)
+	query := `SELECT id, name, number_of_credit, major FROM subjects` + whereClause(conditions) +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
 	var rows *sqlx.Rows
 	var err error
-	if params.Major != "" {
-		query := `SELECT id, name, number_of_credit, major FROM subjects WHERE major = $1 ORDER BY id LIMIT $2 OFFSET $3`
-		if tx != nil {
-			rows, err = tx.QueryxContext(ctx, query, params.Major, params.Limit, params.Offset)
-		} else {
-			rows, err = s.db.QueryxContext(ctx, query, params.Major, params.Limit, params.Offset)
-		}
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
 	} else {
-		query := `SELECT id, name, number_of_credit, major FROM subjects ORDER BY id LIMIT $1 OFFSET $2`
-		if tx != nil {
-			rows, err = tx.QueryxContext(ctx, query, params.Limit, params.Offset)
-		} else {
-			rows, err = s.db.QueryxContext(ctx, query, params.Limit, params.Offset)
-		}
+		rows, err = s.db.QueryxContext(ctx, query, args...)
 	}
 	if err != nil {
 		log.Println("Subject repo, get subjects err: ", err)
 		return nil, err
 	}
-	var subjects []model.Subject
+	subjects := []model.Subject{}
 	defer rows.Close()
 	for rows.Next() {
 		var subject model.Subject
@@ -139,12 +158,158 @@ func (s *subjectRepo) GetSubjectList(ctx context.Context, params dto.GetSubjects
 		}
 		subjects = append(subjects, subject)
 	}
-	if len(subjects) == 0 {
-		return nil, &error2.ResourceNotFoundErr{Resource: "Subject"}
-	}
 	return subjects, nil
 }
 
+func (s *subjectRepo) CountSubjects(ctx context.Context, params dto.GetSubjectsParamDTO, tx *sqlx.Tx) (int, error) {
+	conditions, args := subjectListConditions(params)
+	query := `SELECT COUNT(*) FROM subjects` + whereClause(conditions)
+	var total int
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, args...)
+	} else {
+		err = s.db.GetContext(ctx, &total, query, args...)
+	}
+	if err != nil {
+		log.Println("Subject repo, count subjects err: ", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+func (s *subjectRepo) InsertSubjectPrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite, tx *sqlx.Tx) error {
+	query := `INSERT INTO subject_prerequisites(subject_id, prerequisite_id, type) VALUES (:subject_id, :prerequisite_id, :type)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, prerequisite)
+	} else {
+		_, err = s.db.NamedExecContext(ctx, query, prerequisite)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: "prerequisite already exists"}
+		}
+		log.Println("Subject repo, insert subject prerequisite err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *subjectRepo) UpdateSubjectPrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite, tx *sqlx.Tx) error {
+	query := `UPDATE subject_prerequisites SET type = :type WHERE subject_id = :subject_id AND prerequisite_id = :prerequisite_id`
+
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, query, prerequisite)
+	} else {
+		result, err = s.db.NamedExecContext(ctx, query, prerequisite)
+	}
+	if err != nil {
+		log.Println("Subject repo, update subject prerequisite err: ", err)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Subject repo, update subject prerequisite err: ", err)
+		return err
+	}
+	if affected == 0 {
+		return &error2.ResourceNotFoundErr{Resource: "Subject prerequisite"}
+	}
+	return nil
+}
+
+func (s *subjectRepo) DeleteSubjectPrerequisite(ctx context.Context, subjectId string, prerequisiteId string, tx *sqlx.Tx) error {
+	query := `DELETE FROM subject_prerequisites WHERE subject_id = $1 AND prerequisite_id = $2`
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, subjectId, prerequisiteId)
+	} else {
+		_, err = s.db.ExecContext(ctx, query, subjectId, prerequisiteId)
+	}
+	if err != nil {
+		log.Println("Subject repo, delete subject prerequisite err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (s *subjectRepo) GetPrerequisitesBySubjectId(ctx context.Context, subjectId string, tx *sqlx.Tx) ([]model.SubjectPrerequisite, error) {
+	query := `SELECT subject_prerequisites.subject_id, subject_prerequisites.prerequisite_id, subjects.name as prerequisite_name, subject_prerequisites.type
+			FROM subject_prerequisites
+			JOIN subjects ON subject_prerequisites.prerequisite_id = subjects.id
+			WHERE subject_prerequisites.subject_id = $1
+			ORDER BY subject_prerequisites.prerequisite_id`
+	var rows *sqlx.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, subjectId)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query, subjectId)
+	}
+	if err != nil {
+		log.Println("Subject repo, get subject prerequisites err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var prerequisites []model.SubjectPrerequisite
+	for rows.Next() {
+		var prerequisite model.SubjectPrerequisite
+		err = rows.StructScan(&prerequisite)
+		if err != nil {
+			log.Println("Subject repo, get subject prerequisites err: ", err)
+			return nil, err
+		}
+		prerequisites = append(prerequisites, prerequisite)
+	}
+	if len(prerequisites) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "Subject prerequisites"}
+	}
+	return prerequisites, nil
+}
+
+func (s *subjectRepo) GetAllSubjectPrerequisites(ctx context.Context, tx *sqlx.Tx) ([]model.SubjectPrerequisite, error) {
+	query := `SELECT subject_id, prerequisite_id, type FROM subject_prerequisites`
+	var rows *sqlx.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query)
+	} else {
+		rows, err = s.db.QueryxContext(ctx, query)
+	}
+	if err != nil {
+		log.Println("Subject repo, get all subject prerequisites err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var prerequisites []model.SubjectPrerequisite
+	for rows.Next() {
+		var prerequisite model.SubjectPrerequisite
+		err = rows.StructScan(&prerequisite)
+		if err != nil {
+			log.Println("Subject repo, get all subject prerequisites err: ", err)
+			return nil, err
+		}
+		prerequisites = append(prerequisites, prerequisite)
+	}
+	return prerequisites, nil
+}
+
+// LockSubjectPrerequisites blocks concurrent prerequisite writes until tx ends, so cycle checks stay valid until the
+// write.
+func (s *subjectRepo) LockSubjectPrerequisites(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, `LOCK TABLE subject_prerequisites IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		log.Println("Subject repo, lock subject prerequisites err: ", err)
+		return err
+	}
+	return nil
+}
+
 func NewSubjectRepo(db *sqlx.DB) SubjectRepo {
 	return &subjectRepo{db: db}
 }
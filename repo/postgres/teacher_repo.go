@@ -1,11 +1,13 @@
 package postgres
 
 import (
+	"SchoolManagement/dto"
 	error2 "SchoolManagement/error"
 	"SchoolManagement/model"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"log"
@@ -18,6 +20,8 @@ type TeacherRepo interface {
 	GetTeacherById(ctx context.Context, id string, tx *sqlx.Tx) (model.Teacher, error)
 	DeleteTeacherById(ctx context.Context, id string, tx *sqlx.Tx) error
 	UpdateTeacher(ctx context.Context, teacher model.Teacher, tx *sqlx.Tx) error
+	GetTeacherList(ctx context.Context, params dto.GetTeachersParams, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Teacher, error)
+	CountTeachers(ctx context.Context, params dto.GetTeachersParams, tx *sqlx.Tx) (int, error)
 }
 
 type teacherRepo struct {
@@ -120,6 +124,90 @@ func (r *teacherRepo) UpdateTeacher(ctx context.Context, teacher model.Teacher,
 	return nil
 }
 
+// teacherSortColumns replaces NULL by the lowest value like studentSortColumns.
+var teacherSortColumns = map[string]string{
+	"id":            "users.id",
+	"name":          "name",
+	"date_of_birth": "COALESCE(date_of_birth, '-infinity')",
+	"department":    "COALESCE(department, '')",
+}
+
+func teacherListConditions(params dto.GetTeachersParams) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if params.Department != "" {
+		args = append(args, params.Department)
+		conditions = append(conditions, fmt.Sprintf("department = $%d", len(args)))
+	}
+	if params.Gender != "" {
+		args = append(args, params.Gender)
+		conditions = append(conditions, fmt.Sprintf("gender = $%d", len(args)))
+	}
+	if params.Name != "" {
+		args = append(args, containsPattern(params.Name))
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	return conditions, args
+}
+
+// GetTeacherList returns up to params.Limit matching teachers following cursor, or the first ones when cursor is nil.
+func (r *teacherRepo) GetTeacherList(ctx context.Context, params dto.GetTeachersParams, cursor *dto.Cursor, tx *sqlx.Tx) ([]model.Teacher, error) {
+	conditions, args := teacherListConditions(params)
+	sortColumn, ok := teacherSortColumns[params.SortBy]
+	if !ok {
+		sortColumn = "users.id"
+	}
+	condition, orderBy, args := keysetClause(sortColumn, "users.id", params.SortOrder == "desc", cursor, args)
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	args = append(args, params.Limit)
+	query := `SELECT users.id, name, date_of_birth, gender, email, identity_number, phone_number, address, role, academic_qualification, department
+			FROM users JOIN teachers ON users.id = teachers.id` + whereClause(conditions) +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
+	var rows *sqlx.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = r.db.QueryxContext(ctx, query, args...)
+	}
+	if err != nil {
+		log.Println("Teacher repo, get teachers err:", err)
+		return nil, err
+	}
+	defer rows.Close()
+	teachers := []model.Teacher{}
+	for rows.Next() {
+		var teacher model.Teacher
+		err = rows.StructScan(&teacher)
+		if err != nil {
+			log.Println("Teacher repo, get teachers err:", err)
+			return nil, err
+		}
+		teachers = append(teachers, teacher)
+	}
+	return teachers, nil
+}
+
+func (r *teacherRepo) CountTeachers(ctx context.Context, params dto.GetTeachersParams, tx *sqlx.Tx) (int, error) {
+	conditions, args := teacherListConditions(params)
+	query := `SELECT COUNT(*) FROM users JOIN teachers ON users.id = teachers.id` + whereClause(conditions)
+	var total int
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, args...)
+	} else {
+		err = r.db.GetContext(ctx, &total, query, args...)
+	}
+	if err != nil {
+		log.Println("Teacher repo, count teachers err:", err)
+		return 0, err
+	}
+	return total, nil
+}
+
 func NewTeacherRepo(db *sqlx.DB) TeacherRepo {
 	return &teacherRepo{db: db}
 }
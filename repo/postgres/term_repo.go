@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+	"reflect"
+	"strings"
+)
+
+type TermRepo interface {
+	InsertTerm(ctx context.Context, term model.Term, tx *sqlx.Tx) error
+	UpdateTerm(ctx context.Context, term model.Term, tx *sqlx.Tx) error
+	DeleteTermByCode(ctx context.Context, code string, tx *sqlx.Tx) error
+	GetTermByCode(ctx context.Context, code string, tx *sqlx.Tx) (model.Term, error)
+	GetTermList(ctx context.Context, tx *sqlx.Tx) ([]model.Term, error)
+	GetCurrentTerm(ctx context.Context, tx *sqlx.Tx) (model.Term, error)
+	ClearCurrentTerm(ctx context.Context, tx *sqlx.Tx) error
+}
+
+type termRepo struct {
+	db *sqlx.DB
+}
+
+func (t *termRepo) InsertTerm(ctx context.Context, term model.Term, tx *sqlx.Tx) error {
+	query := `INSERT INTO terms(code, academic_year, semester_number, start_date, end_date, registration_open, registration_close, add_drop_deadline, is_current)
+			VALUES (:code, :academic_year, :semester_number, :start_date, :end_date, :registration_open, :registration_close, :add_drop_deadline, COALESCE(:is_current, FALSE))`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, term)
+	} else {
+		_, err = t.db.NamedExecContext(ctx, query, term)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: "term already exists"}
+		}
+		log.Println("Term repo, insert term err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (t *termRepo) UpdateTerm(ctx context.Context, term model.Term, tx *sqlx.Tx) error {
+	var updateFields []string
+	rt := reflect.TypeOf(term)
+	rv := reflect.ValueOf(term)
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+		if !value.IsZero() {
+			updateFields = append(updateFields, field.Tag.Get("db")+" = :"+field.Tag.Get("db"))
+		}
+	}
+	if len(updateFields) == 0 {
+		return nil
+	}
+	query := `UPDATE terms SET ` + strings.Join(updateFields, ",") + ` WHERE code = :code`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, term)
+	} else {
+		_, err = t.db.NamedExecContext(ctx, query, term)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: "term already exists"}
+		}
+		log.Println("Term repo, update term err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (t *termRepo) DeleteTermByCode(ctx context.Context, code string, tx *sqlx.Tx) error {
+	query := `DELETE FROM terms WHERE code = $1`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, code)
+	} else {
+		_, err = t.db.ExecContext(ctx, query, code)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return &error2.InvalidInputErr{Message: "term still has courses"}
+		}
+		log.Println("Term repo, delete term err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (t *termRepo) GetTermByCode(ctx context.Context, code string, tx *sqlx.Tx) (model.Term, error) {
+	query := `SELECT code, academic_year, semester_number, start_date, end_date, registration_open, registration_close, add_drop_deadline, is_current
+			FROM terms WHERE code = $1`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, code)
+	} else {
+		row = t.db.QueryRowxContext(ctx, query, code)
+	}
+	var term model.Term
+	err := row.StructScan(&term)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return term, &error2.ResourceNotFoundErr{Resource: "Term"}
+		}
+		log.Println("Term repo, get term err: ", err)
+		return term, err
+	}
+	return term, nil
+}
+
+func (t *termRepo) GetTermList(ctx context.Context, tx *sqlx.Tx) ([]model.Term, error) {
+	query := `SELECT code, academic_year, semester_number, start_date, end_date, registration_open, registration_close, add_drop_deadline, is_current
+			FROM terms ORDER BY start_date DESC`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query)
+	} else {
+		rows, err = t.db.QueryxContext(ctx, query)
+	}
+	if err != nil {
+		log.Println("Term repo, get terms err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var terms []model.Term
+	for rows.Next() {
+		var term model.Term
+		err = rows.StructScan(&term)
+		if err != nil {
+			log.Println("Term repo, get terms err: ", err)
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "Term"}
+	}
+	return terms, nil
+}
+
+func (t *termRepo) GetCurrentTerm(ctx context.Context, tx *sqlx.Tx) (model.Term, error) {
+	query := `SELECT code, academic_year, semester_number, start_date, end_date, registration_open, registration_close, add_drop_deadline, is_current
+			FROM terms WHERE is_current`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query)
+	} else {
+		row = t.db.QueryRowxContext(ctx, query)
+	}
+	var term model.Term
+	err := row.StructScan(&term)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return term, &error2.ResourceNotFoundErr{Resource: "Current term"}
+		}
+		log.Println("Term repo, get current term err: ", err)
+		return term, err
+	}
+	return term, nil
+}
+
+func (t *termRepo) ClearCurrentTerm(ctx context.Context, tx *sqlx.Tx) error {
+	query := `UPDATE terms SET is_current = FALSE WHERE is_current`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query)
+	} else {
+		_, err = t.db.ExecContext(ctx, query)
+	}
+	if err != nil {
+		log.Println("Term repo, clear current term err: ", err)
+		return err
+	}
+	return nil
+}
+
+func NewTermRepo(db *sqlx.DB) TermRepo {
+	return &termRepo{db: db}
+}
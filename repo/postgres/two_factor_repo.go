@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+)
+
+type TwoFactorRepo interface {
+	SaveTotp(ctx context.Context, totp model.UserTotp, tx *sqlx.Tx) error
+	GetTotpByUserId(ctx context.Context, userId string, tx *sqlx.Tx) (model.UserTotp, error)
+	EnableTotp(ctx context.Context, userId string, tx *sqlx.Tx) error
+	DeleteTotp(ctx context.Context, userId string, tx *sqlx.Tx) error
+	UseTotpStep(ctx context.Context, userId string, step int64, tx *sqlx.Tx) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string, tx *sqlx.Tx) error
+	UseRecoveryCode(ctx context.Context, userId string, codeHash string, tx *sqlx.Tx) (bool, error)
+	GetTwoFactorPolicies(ctx context.Context, tx *sqlx.Tx) ([]model.TwoFactorPolicy, error)
+	SaveTwoFactorPolicy(ctx context.Context, policy model.TwoFactorPolicy, tx *sqlx.Tx) error
+	IsTwoFactorRequired(ctx context.Context, role string, tx *sqlx.Tx) (bool, error)
+}
+
+type twoFactorRepo struct {
+	db *sqlx.DB
+}
+
+// SaveTotp stores a new secret for the user, replacing the previous one.
+func (t *twoFactorRepo) SaveTotp(ctx context.Context, totp model.UserTotp, tx *sqlx.Tx) error {
+	query := `INSERT INTO user_totp(user_id, secret, enabled, last_used_step, created_at)
+			VALUES (:user_id, :secret, :enabled, :last_used_step, :created_at)
+			ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled,
+				last_used_step = EXCLUDED.last_used_step, created_at = EXCLUDED.created_at`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, totp)
+	} else {
+		_, err = t.db.NamedExecContext(ctx, query, totp)
+	}
+	if err != nil {
+		log.Println("Two factor repo, save totp err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (t *twoFactorRepo) GetTotpByUserId(ctx context.Context, userId string, tx *sqlx.Tx) (model.UserTotp, error) {
+	query := `SELECT user_id, secret, enabled, last_used_step, created_at FROM user_totp WHERE user_id = $1`
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, userId)
+	} else {
+		row = t.db.QueryRowxContext(ctx, query, userId)
+	}
+	var totp model.UserTotp
+	err := row.StructScan(&totp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return totp, &error2.ResourceNotFoundErr{Resource: "Two-factor authentication"}
+		}
+		log.Println("Two factor repo, get totp err: ", err)
+		return totp, err
+	}
+	return totp, nil
+}
+
+func (t *twoFactorRepo) EnableTotp(ctx context.Context, userId string, tx *sqlx.Tx) error {
+	query := `UPDATE user_totp SET enabled = TRUE WHERE user_id = $1`
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, userId)
+	} else {
+		_, err = t.db.ExecContext(ctx, query, userId)
+	}
+	if err != nil {
+		log.Println("Two factor repo, enable totp err: ", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTotp removes the secret of the user together with the recovery codes.
+func (t *twoFactorRepo) DeleteTotp(ctx context.Context, userId string, tx *sqlx.Tx) error {
+	queries := []string{
+		`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_totp WHERE user_id = $1`,
+	}
+	for _, query := range queries {
+		var err error
+		if tx != nil {
+			_, err = tx.ExecContext(ctx, query, userId)
+		} else {
+			_, err = t.db.ExecContext(ctx, query, userId)
+		}
+		if err != nil {
+			log.Println("Two factor repo, delete totp err: ", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// UseTotpStep records that a code of the given step was accepted. It reports false when a code of that step or a
+// later one was accepted before, which means the code is being replayed.
+func (t *twoFactorRepo) UseTotpStep(ctx context.Context, userId string, step int64, tx *sqlx.Tx) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1`
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, step, userId)
+	} else {
+		result, err = t.db.ExecContext(ctx, query, step, userId)
+	}
+	if err != nil {
+		log.Println("Two factor repo, use totp step err: ", err)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Two factor repo, use totp step err: ", err)
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ReplaceRecoveryCodes drops the remaining recovery codes of the user and stores the new ones. It should run in a
+// transaction so that the user never ends up without codes.
+func (t *twoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string, tx *sqlx.Tx) error {
+	query := `DELETE FROM user_recovery_codes WHERE user_id = $1`
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, userId)
+	} else {
+		_, err = t.db.ExecContext(ctx, query, userId)
+	}
+	if err != nil {
+		log.Println("Two factor repo, replace recovery codes err: ", err)
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	query = `INSERT INTO user_recovery_codes(user_id, code_hash) SELECT $1, unnest($2::TEXT[])`
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, userId, pq.Array(codeHashes))
+	} else {
+		_, err = t.db.ExecContext(ctx, query, userId, pq.Array(codeHashes))
+	}
+	if err != nil {
+		log.Println("Two factor repo, replace recovery codes err: ", err)
+		return err
+	}
+	return nil
+}
+
+// UseRecoveryCode deletes the code so that it works only once. It reports false when the user has no such code.
+func (t *twoFactorRepo) UseRecoveryCode(ctx context.Context, userId string, codeHash string, tx *sqlx.Tx) (bool, error) {
+	query := `DELETE FROM user_recovery_codes WHERE user_id = $1 AND code_hash = $2`
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, userId, codeHash)
+	} else {
+		result, err = t.db.ExecContext(ctx, query, userId, codeHash)
+	}
+	if err != nil {
+		log.Println("Two factor repo, use recovery code err: ", err)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Two factor repo, use recovery code err: ", err)
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (t *twoFactorRepo) GetTwoFactorPolicies(ctx context.Context, tx *sqlx.Tx) ([]model.TwoFactorPolicy, error) {
+	query := `SELECT role, required FROM two_factor_policies ORDER BY role`
+	policies := []model.TwoFactorPolicy{}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &policies, query)
+	} else {
+		err = t.db.SelectContext(ctx, &policies, query)
+	}
+	if err != nil {
+		log.Println("Two factor repo, get two factor policies err: ", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (t *twoFactorRepo) SaveTwoFactorPolicy(ctx context.Context, policy model.TwoFactorPolicy, tx *sqlx.Tx) error {
+	query := `INSERT INTO two_factor_policies(role, required) VALUES (:role, :required)
+			ON CONFLICT (role) DO UPDATE SET required = EXCLUDED.required`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, policy)
+	} else {
+		_, err = t.db.NamedExecContext(ctx, query, policy)
+	}
+	if err != nil {
+		log.Println("Two factor repo, save two factor policy err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (t *twoFactorRepo) IsTwoFactorRequired(ctx context.Context, role string, tx *sqlx.Tx) (bool, error) {
+	query := `SELECT required FROM two_factor_policies WHERE role = $1`
+	var required bool
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &required, query, role)
+	} else {
+		err = t.db.GetContext(ctx, &required, query, role)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		log.Println("Two factor repo, get two factor policy err: ", err)
+		return false, err
+	}
+	return required, nil
+}
+
+func NewTwoFactorRepo(db *sqlx.DB) TwoFactorRepo {
+	return &twoFactorRepo{db: db}
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+)
+
+type UserIdentityRepo interface {
+	InsertUserIdentity(ctx context.Context, identity model.UserIdentity, tx *sqlx.Tx) error
+	GetUserIdentity(ctx context.Context, issuer string, subject string, tx *sqlx.Tx) (model.UserIdentity, error)
+}
+
+type userIdentityRepo struct {
+	db *sqlx.DB
+}
+
+func (u *userIdentityRepo) InsertUserIdentity(ctx context.Context, identity model.UserIdentity, tx *sqlx.Tx) error {
+	query := `INSERT INTO user_identities(issuer, subject, user_id, created_at) VALUES (:issuer, :subject, :user_id, :created_at)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, query, identity)
+	} else {
+		_, err = u.db.NamedExecContext(ctx, query, identity)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: pqErr.Constraint}
+		}
+		log.Println("User identity repo, insert user identity err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (u *userIdentityRepo) GetUserIdentity(ctx context.Context, issuer string, subject string, tx *sqlx.Tx) (model.UserIdentity, error) {
+	query := `SELECT issuer, subject, user_id, created_at FROM user_identities WHERE issuer = $1 AND subject = $2`
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, issuer, subject)
+	} else {
+		row = u.db.QueryRowxContext(ctx, query, issuer, subject)
+	}
+	var identity model.UserIdentity
+	err := row.StructScan(&identity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserIdentity{}, &error2.ResourceNotFoundErr{Resource: "User identity"}
+		}
+		log.Println("User identity repo, get user identity err: ", err)
+		return model.UserIdentity{}, err
+	}
+	return identity, nil
+}
+
+func NewUserIdentityRepo(db *sqlx.DB) UserIdentityRepo {
+	return &userIdentityRepo{db: db}
+}
@@ -16,7 +16,9 @@ import (
 type UserRepo interface {
 	InsertUser(ctx context.Context, user model.User, tx *sqlx.Tx) error
 	GetUserById(ctx context.Context, id string, tx *sqlx.Tx) (model.User, error)
+	GetUserByEmail(ctx context.Context, email string, tx *sqlx.Tx) (model.User, error)
 	UpdateUser(ctx context.Context, user model.User, tx *sqlx.Tx) error
+	UpdatePassword(ctx context.Context, id string, password string, tx *sqlx.Tx) error
 	DeleteUserById(ctx context.Context, id string, tx *sqlx.Tx) error
 }
 
@@ -70,6 +72,29 @@ func (u *userRepo) GetUserById(ctx context.Context, id string, tx *sqlx.Tx) (mod
 	return user, nil
 }
 
+func (u *userRepo) GetUserByEmail(ctx context.Context, email string, tx *sqlx.Tx) (model.User, error) {
+	query := `SELECT id, name, date_of_birth, gender, email, identity_number, phone_number, address, password, role FROM users WHERE LOWER(email) = LOWER($1)`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, email)
+	} else {
+		row = u.db.QueryRowxContext(ctx, query, email)
+	}
+
+	var user model.User
+	err := row.StructScan(&user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, &error2.ResourceNotFoundErr{Resource: "User"}
+		}
+		log.Println("User repo, get user by email err :", err)
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
 func (u *userRepo) UpdateUser(ctx context.Context, user model.User, tx *sqlx.Tx) error {
 	var updateFields []string
 	t := reflect.TypeOf(user)
@@ -103,6 +128,31 @@ func (u *userRepo) UpdateUser(ctx context.Context, user model.User, tx *sqlx.Tx)
 	return nil
 }
 
+func (u *userRepo) UpdatePassword(ctx context.Context, id string, password string, tx *sqlx.Tx) error {
+	query := `UPDATE users SET password = $1 WHERE id = $2`
+
+	var err error
+	var result sql.Result
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, query, password, id)
+	} else {
+		result, err = u.db.ExecContext(ctx, query, password, id)
+	}
+	if err != nil {
+		log.Println("User repo, update password err :", err)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("User repo, update password err :", err)
+		return err
+	}
+	if affected == 0 {
+		return &error2.ResourceNotFoundErr{Resource: "User"}
+	}
+	return nil
+}
+
 func (u *userRepo) DeleteUserById(ctx context.Context, id string, tx *sqlx.Tx) error {
 	query := `DELETE FROM users WHERE id = $1`
 	var err error
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+)
+
+type WaitlistRepo interface {
+	InsertWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) error
+	GetWaitlistByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) (model.WaitlistEntry, error)
+	OfferWaitlistEntry(ctx context.Context, id int, expiresAt int64, tx *sqlx.Tx) error
+	DeleteWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) error
+	DeleteExpiredOffers(ctx context.Context, courseId string, now int64, tx *sqlx.Tx) error
+	GetCourseIdsWithExpiredOffers(ctx context.Context, now int64, tx *sqlx.Tx) ([]string, error)
+}
+
+type waitlistRepo struct {
+	db *sqlx.DB
+}
+
+// InsertWaitlistEntry appends the student to the end of the waitlist, the caller must hold the course row lock.
+func (w *waitlistRepo) InsertWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) error {
+	query := `INSERT INTO course_waitlists(course_id, student_id, position, status)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM course_waitlists WHERE course_id = $1), $3)`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, courseId, studentId, model.WaitlistStatusWaiting)
+	} else {
+		_, err = w.db.ExecContext(ctx, query, courseId, studentId, model.WaitlistStatusWaiting)
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return &error2.UniqueConstraintErr{Message: "student already on waitlist"}
+		}
+		log.Println("Waitlist repo, insert waitlist entry err: ", err)
+		return err
+	}
+	return nil
+}
+
+// GetWaitlistByCourseId returns the waitlist in order, positions are renumbered from 1.
+func (w *waitlistRepo) GetWaitlistByCourseId(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.WaitlistEntry, error) {
+	query := `SELECT course_waitlists.id, course_waitlists.course_id, course_waitlists.student_id, users.name AS student_name,
+				ROW_NUMBER() OVER (ORDER BY course_waitlists.position) AS position,
+				course_waitlists.status, COALESCE(course_waitlists.offer_expires_at, 0) AS offer_expires_at
+			FROM course_waitlists
+			JOIN users ON course_waitlists.student_id = users.id
+			WHERE course_waitlists.course_id = $1
+			ORDER BY course_waitlists.position`
+
+	var err error
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, courseId)
+	} else {
+		rows, err = w.db.QueryxContext(ctx, query, courseId)
+	}
+	if err != nil {
+		log.Println("Waitlist repo, get waitlist err: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []model.WaitlistEntry
+	for rows.Next() {
+		var entry model.WaitlistEntry
+		err = rows.StructScan(&entry)
+		if err != nil {
+			log.Println("Waitlist repo, get waitlist err: ", err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (w *waitlistRepo) GetWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) (model.WaitlistEntry, error) {
+	query := `SELECT id, course_id, student_id, position, status, COALESCE(offer_expires_at, 0) AS offer_expires_at
+			FROM course_waitlists WHERE course_id = $1 AND student_id = $2`
+
+	var row *sqlx.Row
+	if tx != nil {
+		row = tx.QueryRowxContext(ctx, query, courseId, studentId)
+	} else {
+		row = w.db.QueryRowxContext(ctx, query, courseId, studentId)
+	}
+	var entry model.WaitlistEntry
+	err := row.StructScan(&entry)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entry, &error2.ResourceNotFoundErr{Resource: "waitlist entry"}
+		}
+		log.Println("Waitlist repo, get waitlist entry err: ", err)
+		return entry, err
+	}
+	return entry, nil
+}
+
+func (w *waitlistRepo) OfferWaitlistEntry(ctx context.Context, id int, expiresAt int64, tx *sqlx.Tx) error {
+	query := `UPDATE course_waitlists SET status = $1, offer_expires_at = $2 WHERE id = $3`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, model.WaitlistStatusOffered, expiresAt, id)
+	} else {
+		_, err = w.db.ExecContext(ctx, query, model.WaitlistStatusOffered, expiresAt, id)
+	}
+	if err != nil {
+		log.Println("Waitlist repo, offer waitlist entry err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (w *waitlistRepo) DeleteWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) error {
+	query := `DELETE FROM course_waitlists WHERE course_id = $1 AND student_id = $2`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, courseId, studentId)
+	} else {
+		_, err = w.db.ExecContext(ctx, query, courseId, studentId)
+	}
+	if err != nil {
+		log.Println("Waitlist repo, delete waitlist entry err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (w *waitlistRepo) DeleteExpiredOffers(ctx context.Context, courseId string, now int64, tx *sqlx.Tx) error {
+	query := `DELETE FROM course_waitlists WHERE course_id = $1 AND status = $2 AND offer_expires_at <= $3`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, courseId, model.WaitlistStatusOffered, now)
+	} else {
+		_, err = w.db.ExecContext(ctx, query, courseId, model.WaitlistStatusOffered, now)
+	}
+	if err != nil {
+		log.Println("Waitlist repo, delete expired offers err: ", err)
+		return err
+	}
+	return nil
+}
+
+func (w *waitlistRepo) GetCourseIdsWithExpiredOffers(ctx context.Context, now int64, tx *sqlx.Tx) ([]string, error) {
+	query := `SELECT DISTINCT course_id FROM course_waitlists WHERE status = $1 AND offer_expires_at <= $2 ORDER BY course_id`
+
+	var err error
+	var courseIds []string
+	if tx != nil {
+		err = tx.SelectContext(ctx, &courseIds, query, model.WaitlistStatusOffered, now)
+	} else {
+		err = w.db.SelectContext(ctx, &courseIds, query, model.WaitlistStatusOffered, now)
+	}
+	if err != nil {
+		log.Println("Waitlist repo, get courses with expired offers err: ", err)
+		return nil, err
+	}
+	return courseIds, nil
+}
+
+func NewWaitlistRepo(db *sqlx.DB) WaitlistRepo {
+	return &waitlistRepo{db: db}
+}
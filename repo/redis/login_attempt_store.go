@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIp      = "ip"
+	// PasswordResetScopeEmail and PasswordResetScopeIp count password reset requests rather than failed logins.
+	PasswordResetScopeEmail = "reset_email"
+	PasswordResetScopeIp    = "reset_ip"
+)
+
+// LoginAttemptStore counts failed logins per account or per IP address and blocks further attempts for a while. The
+// password reset requests are counted in it as well.
+type LoginAttemptStore interface {
+	GetBlockedFor(ctx context.Context, scope string, subject string) (time.Duration, error)
+	RecordFailure(ctx context.Context, scope string, subject string, window time.Duration) (int, error)
+	Block(ctx context.Context, scope string, subject string, duration time.Duration) error
+	Reset(ctx context.Context, scope string, subject string) error
+}
+
+type loginAttemptStore struct {
+	redisClient *redis.Client
+}
+
+func getLoginFailuresKey(scope string, subject string) string {
+	return fmt.Sprintf("login_failures#%s#%s", scope, subject)
+}
+
+func getLoginBlockedKey(scope string, subject string) string {
+	return fmt.Sprintf("login_blocked#%s#%s", scope, subject)
+}
+
+// GetBlockedFor returns how long attempts are still blocked, 0 when they are not.
+func (l *loginAttemptStore) GetBlockedFor(ctx context.Context, scope string, subject string) (time.Duration, error) {
+	ttl, err := l.redisClient.PTTL(ctx, getLoginBlockedKey(scope, subject)).Result()
+	if err != nil {
+		log.Println("Login attempt store, get blocked for err :", err)
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RecordFailure counts a failed attempt and returns the failures counted so far. The count is forgotten once no
+// attempt fails for the given window.
+func (l *loginAttemptStore) RecordFailure(ctx context.Context, scope string, subject string, window time.Duration) (int, error) {
+	key := getLoginFailuresKey(scope, subject)
+	var incr *redis.IntCmd
+	_, err := l.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		log.Println("Login attempt store, record failure err :", err)
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (l *loginAttemptStore) Block(ctx context.Context, scope string, subject string, duration time.Duration) error {
+	_, err := l.redisClient.Set(ctx, getLoginBlockedKey(scope, subject), 1, duration).Result()
+	if err != nil {
+		log.Println("Login attempt store, block err :", err)
+		return err
+	}
+	return nil
+}
+
+func (l *loginAttemptStore) Reset(ctx context.Context, scope string, subject string) error {
+	_, err := l.redisClient.Del(ctx, getLoginFailuresKey(scope, subject), getLoginBlockedKey(scope, subject)).Result()
+	if err != nil {
+		log.Println("Login attempt store, reset err :", err)
+		return err
+	}
+	return nil
+}
+
+func NewLoginAttemptStore(redisClient *redis.Client) LoginAttemptStore {
+	return &loginAttemptStore{redisClient: redisClient}
+}
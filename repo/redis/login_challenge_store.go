@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"SchoolManagement/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+type LoginChallengeStore interface {
+	SaveChallenge(ctx context.Context, tokenHash string, challenge model.LoginChallenge, ttl time.Duration) error
+	GetChallenge(ctx context.Context, tokenHash string) (model.LoginChallenge, bool, error)
+	ConsumeChallenge(ctx context.Context, tokenHash string) (bool, error)
+}
+
+type loginChallengeStore struct {
+	redisClient *redis.Client
+}
+
+func getLoginChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("login_challenge#%s", tokenHash)
+}
+
+func (l *loginChallengeStore) SaveChallenge(ctx context.Context, tokenHash string, challenge model.LoginChallenge, ttl time.Duration) error {
+	challengeBytes, err := json.Marshal(challenge)
+	if err != nil {
+		log.Println("Login challenge store, save challenge err :", err)
+		return err
+	}
+	_, err = l.redisClient.Set(ctx, getLoginChallengeKey(tokenHash), challengeBytes, ttl).Result()
+	if err != nil {
+		log.Println("Login challenge store, save challenge err :", err)
+		return err
+	}
+	return nil
+}
+
+// GetChallenge leaves the challenge in place, so that a mistyped code can be corrected.
+func (l *loginChallengeStore) GetChallenge(ctx context.Context, tokenHash string) (model.LoginChallenge, bool, error) {
+	res, err := l.redisClient.Get(ctx, getLoginChallengeKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return model.LoginChallenge{}, false, nil
+		}
+		log.Println("Login challenge store, get challenge err :", err)
+		return model.LoginChallenge{}, false, err
+	}
+	var challenge model.LoginChallenge
+	err = json.Unmarshal([]byte(res), &challenge)
+	if err != nil {
+		log.Println("Login challenge store, get challenge err :", err)
+		return model.LoginChallenge{}, false, err
+	}
+	return challenge, true, nil
+}
+
+// ConsumeChallenge removes a completed challenge. It reports false when the challenge was already gone, for example
+// because a concurrent request completed it first.
+func (l *loginChallengeStore) ConsumeChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	count, err := l.redisClient.Del(ctx, getLoginChallengeKey(tokenHash)).Result()
+	if err != nil {
+		log.Println("Login challenge store, consume challenge err :", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func NewLoginChallengeStore(redisClient *redis.Client) LoginChallengeStore {
+	return &loginChallengeStore{redisClient: redisClient}
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"SchoolManagement/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+type OidcStateStore interface {
+	SaveState(ctx context.Context, stateHash string, state model.OidcLoginState, ttl time.Duration) error
+	ConsumeState(ctx context.Context, stateHash string) (model.OidcLoginState, bool, error)
+}
+
+type oidcStateStore struct {
+	redisClient *redis.Client
+}
+
+func getOidcStateKey(stateHash string) string {
+	return fmt.Sprintf("oidc_state#%s", stateHash)
+}
+
+func (o *oidcStateStore) SaveState(ctx context.Context, stateHash string, state model.OidcLoginState, ttl time.Duration) error {
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		log.Println("Oidc state store, save state err :", err)
+		return err
+	}
+	_, err = o.redisClient.Set(ctx, getOidcStateKey(stateHash), stateBytes, ttl).Result()
+	if err != nil {
+		log.Println("Oidc state store, save state err :", err)
+		return err
+	}
+	return nil
+}
+
+// ConsumeState returns the state and removes it in one step, so that a callback cannot be replayed.
+func (o *oidcStateStore) ConsumeState(ctx context.Context, stateHash string) (model.OidcLoginState, bool, error) {
+	res, err := o.redisClient.GetDel(ctx, getOidcStateKey(stateHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return model.OidcLoginState{}, false, nil
+		}
+		log.Println("Oidc state store, consume state err :", err)
+		return model.OidcLoginState{}, false, err
+	}
+	var state model.OidcLoginState
+	err = json.Unmarshal([]byte(res), &state)
+	if err != nil {
+		log.Println("Oidc state store, consume state err :", err)
+		return model.OidcLoginState{}, false, err
+	}
+	return state, true, nil
+}
+
+func NewOidcStateStore(redisClient *redis.Client) OidcStateStore {
+	return &oidcStateStore{redisClient: redisClient}
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+type PasswordResetStore interface {
+	SaveResetToken(ctx context.Context, tokenHash string, userId string, ttl time.Duration) error
+	ConsumeResetToken(ctx context.Context, tokenHash string) (string, bool, error)
+}
+
+type passwordResetStore struct {
+	redisClient *redis.Client
+}
+
+func getPasswordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password_reset#%s", tokenHash)
+}
+
+func (p *passwordResetStore) SaveResetToken(ctx context.Context, tokenHash string, userId string, ttl time.Duration) error {
+	_, err := p.redisClient.Set(ctx, getPasswordResetKey(tokenHash), userId, ttl).Result()
+	if err != nil {
+		log.Println("Password reset store, save reset token err :", err)
+		return err
+	}
+	return nil
+}
+
+// ConsumeResetToken removes the token so that it can be used only once and returns the user it was issued to. It
+// reports false when the token does not exist or has expired.
+func (p *passwordResetStore) ConsumeResetToken(ctx context.Context, tokenHash string) (string, bool, error) {
+	userId, err := p.redisClient.GetDel(ctx, getPasswordResetKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", false, nil
+		}
+		log.Println("Password reset store, consume reset token err :", err)
+		return "", false, err
+	}
+	return userId, true, nil
+}
+
+func NewPasswordResetStore(redisClient *redis.Client) PasswordResetStore {
+	return &passwordResetStore{redisClient: redisClient}
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"SchoolManagement/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"time"
+)
+
+type RefreshTokenStore interface {
+	SaveRefreshToken(ctx context.Context, tokenHash string, token model.RefreshToken) error
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, bool, error)
+	GetUsedRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, bool, error)
+	IsFamilyActive(ctx context.Context, familyId string) (bool, error)
+	RevokeFamily(ctx context.Context, familyId string) error
+	RevokeUserFamilies(ctx context.Context, userId string) error
+}
+
+type refreshTokenStore struct {
+	redisClient *redis.Client
+}
+
+func getRefreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("refresh_token#%s", tokenHash)
+}
+
+func getUsedRefreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("refresh_token_used#%s", tokenHash)
+}
+
+func getRefreshFamilyKey(familyId string) string {
+	return fmt.Sprintf("refresh_family#%s", familyId)
+}
+
+func getUserRefreshFamiliesKey(userId string) string {
+	return fmt.Sprintf("user_refresh_families#%s", userId)
+}
+
+// SaveRefreshToken stores a fresh token and keeps its family alive at least as long as the token.
+func (r *refreshTokenStore) SaveRefreshToken(ctx context.Context, tokenHash string, token model.RefreshToken) error {
+	tokenBytes, err := json.Marshal(token)
+	if err != nil {
+		log.Println("Refresh token store, save refresh token err :", err)
+		return err
+	}
+	ttl := time.Until(time.Unix(token.ExpiresAt, 0))
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, getRefreshTokenKey(tokenHash), tokenBytes, ttl)
+		pipe.Set(ctx, getRefreshFamilyKey(token.FamilyId), token.UserId, ttl)
+		pipe.SAdd(ctx, getUserRefreshFamiliesKey(token.UserId), token.FamilyId)
+		pipe.Expire(ctx, getUserRefreshFamiliesKey(token.UserId), ttl)
+		return nil
+	})
+	if err != nil {
+		log.Println("Refresh token store, save refresh token err :", err)
+		return err
+	}
+	return nil
+}
+
+// ConsumeRefreshToken removes a fresh token so that it can be used only once, and remembers it as used until it
+// would have expired. It reports false when the token is not fresh.
+func (r *refreshTokenStore) ConsumeRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, bool, error) {
+	res, err := r.redisClient.GetDel(ctx, getRefreshTokenKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return model.RefreshToken{}, false, nil
+		}
+		log.Println("Refresh token store, consume refresh token err :", err)
+		return model.RefreshToken{}, false, err
+	}
+	var token model.RefreshToken
+	err = json.Unmarshal([]byte(res), &token)
+	if err != nil {
+		log.Println("Refresh token store, consume refresh token err :", err)
+		return model.RefreshToken{}, false, err
+	}
+	_, err = r.redisClient.Set(ctx, getUsedRefreshTokenKey(tokenHash), res, time.Until(time.Unix(token.ExpiresAt, 0))).Result()
+	if err != nil {
+		log.Println("Refresh token store, consume refresh token err :", err)
+		return model.RefreshToken{}, false, err
+	}
+	return token, true, nil
+}
+
+func (r *refreshTokenStore) GetUsedRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, bool, error) {
+	res, err := r.redisClient.Get(ctx, getUsedRefreshTokenKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return model.RefreshToken{}, false, nil
+		}
+		log.Println("Refresh token store, get used refresh token err :", err)
+		return model.RefreshToken{}, false, err
+	}
+	var token model.RefreshToken
+	err = json.Unmarshal([]byte(res), &token)
+	if err != nil {
+		log.Println("Refresh token store, get used refresh token err :", err)
+		return model.RefreshToken{}, false, err
+	}
+	return token, true, nil
+}
+
+func (r *refreshTokenStore) IsFamilyActive(ctx context.Context, familyId string) (bool, error) {
+	count, err := r.redisClient.Exists(ctx, getRefreshFamilyKey(familyId)).Result()
+	if err != nil {
+		log.Println("Refresh token store, check refresh family err :", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RevokeFamily invalidates every token rotated from the same login, including the ones not used yet.
+func (r *refreshTokenStore) RevokeFamily(ctx context.Context, familyId string) error {
+	_, err := r.redisClient.Del(ctx, getRefreshFamilyKey(familyId)).Result()
+	if err != nil {
+		log.Println("Refresh token store, revoke refresh family err :", err)
+		return err
+	}
+	return nil
+}
+
+// RevokeUserFamilies revokes every family the user has logged in with.
+func (r *refreshTokenStore) RevokeUserFamilies(ctx context.Context, userId string) error {
+	familiesKey := getUserRefreshFamiliesKey(userId)
+	familyIds, err := r.redisClient.SMembers(ctx, familiesKey).Result()
+	if err != nil {
+		log.Println("Refresh token store, revoke user refresh families err :", err)
+		return err
+	}
+	keys := []string{familiesKey}
+	for _, familyId := range familyIds {
+		keys = append(keys, getRefreshFamilyKey(familyId))
+	}
+	_, err = r.redisClient.Del(ctx, keys...).Result()
+	if err != nil {
+		log.Println("Refresh token store, revoke user refresh families err :", err)
+		return err
+	}
+	return nil
+}
+
+func NewRefreshTokenStore(redisClient *redis.Client) RefreshTokenStore {
+	return &refreshTokenStore{redisClient: redisClient}
+}
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"strconv"
+	"time"
+)
+
+// SessionStore keeps the access tokens that were revoked before they expired.
+type SessionStore interface {
+	DenyToken(ctx context.Context, jti string, expiresAt int64) error
+	IsTokenDenied(ctx context.Context, jti string) (bool, error)
+	RevokeUserSessions(ctx context.Context, userId string) error
+	GetUserSessionsRevokedAt(ctx context.Context, userId string) (int64, bool, error)
+}
+
+type sessionStore struct {
+	tokenDuration time.Duration
+	redisClient   *redis.Client
+}
+
+func getDeniedTokenKey(jti string) string {
+	return fmt.Sprintf("denied_token#%s", jti)
+}
+
+func getUserSessionsRevokedKey(userId string) string {
+	return fmt.Sprintf("user_sessions_revoked#%s", userId)
+}
+
+// DenyToken denies a single access token until it would have expired anyway.
+func (s *sessionStore) DenyToken(ctx context.Context, jti string, expiresAt int64) error {
+	ttl := time.Until(time.Unix(expiresAt, 0))
+	if ttl <= 0 {
+		return nil
+	}
+	_, err := s.redisClient.Set(ctx, getDeniedTokenKey(jti), 1, ttl).Result()
+	if err != nil {
+		log.Println("Session store, deny token err :", err)
+		return err
+	}
+	return nil
+}
+
+func (s *sessionStore) IsTokenDenied(ctx context.Context, jti string) (bool, error) {
+	count, err := s.redisClient.Exists(ctx, getDeniedTokenKey(jti)).Result()
+	if err != nil {
+		log.Println("Session store, check denied token err :", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RevokeUserSessions denies every access token issued to the user up to now. The mark is in unix milliseconds and
+// only has to outlive the longest lived access token.
+func (s *sessionStore) RevokeUserSessions(ctx context.Context, userId string) error {
+	_, err := s.redisClient.Set(ctx, getUserSessionsRevokedKey(userId), time.Now().UnixMilli(), s.tokenDuration).Result()
+	if err != nil {
+		log.Println("Session store, revoke user sessions err :", err)
+		return err
+	}
+	return nil
+}
+
+func (s *sessionStore) GetUserSessionsRevokedAt(ctx context.Context, userId string) (int64, bool, error) {
+	res, err := s.redisClient.Get(ctx, getUserSessionsRevokedKey(userId)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, false, nil
+		}
+		log.Println("Session store, get user sessions revoked at err :", err)
+		return 0, false, err
+	}
+	revokedAt, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		log.Println("Session store, get user sessions revoked at err :", err)
+		return 0, false, err
+	}
+	return revokedAt, true, nil
+}
+
+func NewSessionStore(redisClient *redis.Client, tokenDuration time.Duration) SessionStore {
+	return &sessionStore{redisClient: redisClient, tokenDuration: tokenDuration}
+}
@@ -3,41 +3,255 @@ package service
 import (
 	"SchoolManagement/dto/response"
 	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo"
 	"SchoolManagement/repo/postgres"
+	"SchoolManagement/repo/redis"
 	"SchoolManagement/utils"
 	"context"
+	"errors"
+	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
+	"log"
+	"time"
 )
 
 type AuthService interface {
 	Login(ctx context.Context, id string, password string) (response.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (response.LoginResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	RevokeUserSessions(ctx context.Context, userId string) error
+	UnlockAccount(ctx context.Context, userId string) error
+	LoginTwoFactor(ctx context.Context, challengeToken string, code string) (response.LoginResponse, error)
+	EnrollTwoFactorWithChallenge(ctx context.Context, challengeToken string) (response.TwoFactorEnrollmentResponse, error)
+	StartOidcLogin(ctx context.Context) (response.OidcLoginResponse, error)
+	CompleteOidcLogin(ctx context.Context, code string, state string, browserBinding string) (response.LoginResponse, error)
 }
 
 type authService struct {
-	userRepo postgres.UserRepo
-	jwtUtils utils.JwtUtils
+	loginThrottle
+	userRepo             postgres.UserRepo
+	twoFactorRepo        postgres.TwoFactorRepo
+	userIdentityRepo     postgres.UserIdentityRepo
+	studentRepo          postgres.StudentRepo
+	transactionManager   repo.TransactionManager
+	jwtUtils             utils.JwtUtils
+	refreshTokenStore    redis.RefreshTokenStore
+	sessionStore         redis.SessionStore
+	loginChallengeStore  redis.LoginChallengeStore
+	oidcStateStore       redis.OidcStateStore
+	oidcClient           utils.OidcClient
+	authMiddleware       middleware.AuthMiddleware
+	refreshTokenDuration time.Duration
+	oidcConfig           utils.OidcConfig
 }
 
+// Login answers a wrong id and a wrong password alike, in the same time, so that it cannot be used to find out
+// which ids exist. Failures are counted per account and per IP address and block further attempts for a while.
+// Users with two-factor authentication get a challenge to complete with LoginTwoFactor instead of the tokens.
 func (a *authService) Login(ctx context.Context, id string, password string) (response.LoginResponse, error) {
-	user, err := a.userRepo.GetUserById(ctx, id, nil)
+	ip := clientIpFromContext(ctx)
+	blocked, err := a.isLoginBlocked(ctx, id, ip)
 	if err != nil {
 		return response.LoginResponse{}, err
 	}
+	if blocked {
+		a.recordAuthEvent(ctx, id, ip, model.AuthEventLoginBlocked)
+		return response.LoginResponse{}, error2.TooManyLoginAttemptsErr
+	}
+	user, err := a.userRepo.GetUserById(ctx, id, nil)
+	if err != nil {
+		var notFoundErr *error2.ResourceNotFoundErr
+		if !errors.As(err, &notFoundErr) {
+			return response.LoginResponse{}, err
+		}
+		user.Password = dummyPasswordHash
+	}
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil || user.Id == "" {
+		a.recordAuthEvent(ctx, id, ip, model.AuthEventLoginFailed)
+		err = a.recordLoginFailure(ctx, id, ip)
+		if err != nil {
+			return response.LoginResponse{}, err
+		}
+		return response.LoginResponse{}, error2.InvalidCredentialsErr
+	}
+	challenge, err := a.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if challenge != nil {
+		a.recordAuthEvent(ctx, id, ip, model.AuthEventTwoFactorChallenged)
+		return a.issueChallenge(ctx, *challenge)
+	}
+	return a.completeLogin(ctx, user, ip)
+}
+
+// completeLogin clears the failures of the account and starts a new session.
+func (a *authService) completeLogin(ctx context.Context, user model.User, ip string) (response.LoginResponse, error) {
+	err := a.loginAttemptStore.Reset(ctx, redis.LoginScopeAccount, user.Id)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	a.recordAuthEvent(ctx, user.Id, ip, model.AuthEventLoginSucceeded)
+	familyId, err := utils.GenerateToken()
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	return a.issueTokens(ctx, user, familyId)
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh token. A refresh token works once:
+// presenting it again means it leaked, so every token of its family is revoked.
+func (a *authService) Refresh(ctx context.Context, refreshToken string) (response.LoginResponse, error) {
+	tokenHash := utils.HashToken(refreshToken)
+	token, fresh, err := a.refreshTokenStore.ConsumeRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !fresh {
+		usedToken, used, e := a.refreshTokenStore.GetUsedRefreshToken(ctx, tokenHash)
+		if e != nil {
+			return response.LoginResponse{}, e
+		}
+		if used {
+			log.Println("Auth service, refresh token reused, revoking family of user ", usedToken.UserId)
+			e = a.refreshTokenStore.RevokeFamily(ctx, usedToken.FamilyId)
+			if e != nil {
+				return response.LoginResponse{}, e
+			}
+		}
+		return response.LoginResponse{}, &error2.UnauthorizedErr{Message: "Invalid refresh token"}
+	}
+	active, err := a.refreshTokenStore.IsFamilyActive(ctx, token.FamilyId)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !active {
+		return response.LoginResponse{}, &error2.UnauthorizedErr{Message: "Invalid refresh token"}
+	}
+	// The role is read again so that a refreshed access token never outlives a role change.
+	user, err := a.userRepo.GetUserById(ctx, token.UserId, nil)
+	if err != nil {
+		var notFoundErr *error2.ResourceNotFoundErr
+		if errors.As(err, &notFoundErr) {
+			return response.LoginResponse{}, &error2.UnauthorizedErr{Message: "Invalid refresh token"}
+		}
+		return response.LoginResponse{}, err
+	}
+	return a.issueTokens(ctx, user, token.FamilyId)
+}
+
+// Logout denies the access token of the request. When the refresh token of the session is given, its whole family
+// is revoked as well so that the session cannot be refreshed back to life.
+func (a *authService) Logout(ctx context.Context, refreshToken string) error {
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	jti, _ := claims["jti"].(string)
+	expiresAt, _ := claims["exp"].(float64)
+	err := a.sessionStore.DenyToken(ctx, jti, int64(expiresAt))
+	if err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	token, fresh, err := a.refreshTokenStore.ConsumeRefreshToken(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if fresh && token.UserId == claims["userId"].(string) {
+		return a.refreshTokenStore.RevokeFamily(ctx, token.FamilyId)
+	}
+	return nil
+}
+
+func (a *authService) RevokeUserSessions(ctx context.Context, userId string) error {
+	err := a.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to revoke sessions"}
+	}
+	_, err = a.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return err
+	}
+	return revokeUserSessions(ctx, a.sessionStore, a.refreshTokenStore, userId)
+}
+
+func (a *authService) UnlockAccount(ctx context.Context, userId string) error {
+	err := a.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to unlock accounts"}
+	}
+	_, err = a.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return err
+	}
+	err = a.loginAttemptStore.Reset(ctx, redis.LoginScopeAccount, userId)
+	if err != nil {
+		return err
+	}
+	a.recordAuthEvent(ctx, userId, clientIpFromContext(ctx), model.AuthEventAccountUnlocked)
+	return nil
+}
+
+// revokeUserSessions invalidates every access token issued to the user so far and every refresh token family.
+func revokeUserSessions(ctx context.Context, sessionStore redis.SessionStore, refreshTokenStore redis.RefreshTokenStore, userId string) error {
+	err := sessionStore.RevokeUserSessions(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return refreshTokenStore.RevokeUserFamilies(ctx, userId)
+}
+
+func (a *authService) issueTokens(ctx context.Context, user model.User, familyId string) (response.LoginResponse, error) {
+	accessToken, expireTime, err := a.jwtUtils.CreateToken(user.Id, user.Role)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	refreshToken, err := utils.GenerateToken()
 	if err != nil {
-		return response.LoginResponse{}, error2.WrongPasswordErr
+		return response.LoginResponse{}, err
 	}
-	token, expireTime, err := a.jwtUtils.CreateToken(id, user.Role)
+	refreshExpireTime := time.Now().Add(a.refreshTokenDuration).Unix()
+	err = a.refreshTokenStore.SaveRefreshToken(ctx, utils.HashToken(refreshToken), model.RefreshToken{
+		UserId:    user.Id,
+		FamilyId:  familyId,
+		ExpiresAt: refreshExpireTime,
+	})
 	if err != nil {
 		return response.LoginResponse{}, err
 	}
 	return response.LoginResponse{
-		AccessToken: token,
-		ExpiresIn:   expireTime,
-		Role:        user.Role,
+		AccessToken:      accessToken,
+		ExpiresIn:        expireTime,
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: refreshExpireTime,
+		Role:             user.Role,
 	}, nil
 }
 
-func NewAuthService(userRepo postgres.UserRepo, jwtUtils utils.JwtUtils) AuthService {
-	return &authService{userRepo: userRepo, jwtUtils: jwtUtils}
+func NewAuthService(userRepo postgres.UserRepo, authEventRepo postgres.AuthEventRepo, twoFactorRepo postgres.TwoFactorRepo, userIdentityRepo postgres.UserIdentityRepo, studentRepo postgres.StudentRepo, transactionManager repo.TransactionManager, jwtUtils utils.JwtUtils, refreshTokenStore redis.RefreshTokenStore, sessionStore redis.SessionStore, loginAttemptStore redis.LoginAttemptStore, loginChallengeStore redis.LoginChallengeStore, oidcStateStore redis.OidcStateStore, oidcClient utils.OidcClient, authMiddleware middleware.AuthMiddleware, refreshTokenDuration time.Duration, accountPolicy utils.LoginThrottlePolicy, ipPolicy utils.LoginThrottlePolicy, oidcConfig utils.OidcConfig) AuthService {
+	return &authService{
+		loginThrottle: loginThrottle{
+			loginAttemptStore: loginAttemptStore,
+			authEventRepo:     authEventRepo,
+			accountPolicy:     accountPolicy,
+			ipPolicy:          ipPolicy,
+		},
+		userRepo:             userRepo,
+		twoFactorRepo:        twoFactorRepo,
+		userIdentityRepo:     userIdentityRepo,
+		studentRepo:          studentRepo,
+		transactionManager:   transactionManager,
+		jwtUtils:             jwtUtils,
+		refreshTokenStore:    refreshTokenStore,
+		sessionStore:         sessionStore,
+		loginChallengeStore:  loginChallengeStore,
+		oidcStateStore:       oidcStateStore,
+		oidcClient:           oidcClient,
+		authMiddleware:       authMiddleware,
+		refreshTokenDuration: refreshTokenDuration,
+		oidcConfig:           oidcConfig,
+	}
 }
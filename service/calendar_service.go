@@ -0,0 +1,114 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+type CalendarService interface {
+	CreateCalendarToken(ctx context.Context, userId string) (string, error)
+	RevokeCalendarToken(ctx context.Context, userId string) error
+	ExportCalendar(ctx context.Context, userId string) (string, error)
+	GetCalendarFeed(ctx context.Context, token string) (string, error)
+}
+
+type calendarService struct {
+	calendarTokenRepo postgres.CalendarTokenRepo
+	courseRepo        postgres.CourseRepo
+	userRepo          postgres.UserRepo
+	termRepo          postgres.TermRepo
+}
+
+// CreateCalendarToken issues the token of the user's calendar feed. Issuing a new one revokes the previous token.
+func (c *calendarService) CreateCalendarToken(ctx context.Context, userId string) (string, error) {
+	err := checkSelfOrAdmin(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	_, err = c.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return "", err
+	}
+	token, err := utils.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	err = c.calendarTokenRepo.SaveCalendarToken(ctx, model.CalendarToken{
+		UserId:    userId,
+		TokenHash: utils.HashToken(token),
+		CreatedAt: time.Now().Unix(),
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (c *calendarService) RevokeCalendarToken(ctx context.Context, userId string) error {
+	err := checkSelfOrAdmin(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return c.calendarTokenRepo.DeleteCalendarToken(ctx, userId, nil)
+}
+
+func (c *calendarService) ExportCalendar(ctx context.Context, userId string) (string, error) {
+	err := checkSelfOrAdmin(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	return c.buildCalendar(ctx, userId)
+}
+
+// GetCalendarFeed serves the feed polled by calendar apps, which authenticate with the calendar token instead of a JWT.
+func (c *calendarService) GetCalendarFeed(ctx context.Context, token string) (string, error) {
+	calendarToken, err := c.calendarTokenRepo.GetCalendarTokenByHash(ctx, utils.HashToken(token), nil)
+	if err != nil {
+		var notFoundErr *error2.ResourceNotFoundErr
+		if errors.As(err, &notFoundErr) {
+			return "", &error2.UnauthorizedErr{Message: "Invalid calendar token"}
+		}
+		return "", err
+	}
+	return c.buildCalendar(ctx, calendarToken.UserId)
+}
+
+// buildCalendar renders the schedules as stored right now, so added or deleted schedules show up at the next poll.
+func (c *calendarService) buildCalendar(ctx context.Context, userId string) (string, error) {
+	user, err := c.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return "", err
+	}
+	courses, err := getUserCourses(ctx, c.courseRepo, c.termRepo, user, func(term model.Term) bool {
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	courseIds := make([]string, 0, len(courses))
+	for id := range courses {
+		courseIds = append(courseIds, id)
+	}
+	var schedules []model.CourseSchedule
+	if len(courseIds) > 0 {
+		schedules, err = c.courseRepo.GetSchedulesByCourseIds(ctx, courseIds, nil)
+		if err != nil {
+			return "", err
+		}
+	}
+	return utils.BuildICalendar(schedules, courses, time.Now())
+}
+
+func NewCalendarService(calendarTokenRepo postgres.CalendarTokenRepo, courseRepo postgres.CourseRepo, userRepo postgres.UserRepo, termRepo postgres.TermRepo) CalendarService {
+	return &calendarService{
+		calendarTokenRepo: calendarTokenRepo,
+		courseRepo:        courseRepo,
+		userRepo:          userRepo,
+		termRepo:          termRepo,
+	}
+}
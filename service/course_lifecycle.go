@@ -0,0 +1,39 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"SchoolManagement/repo/postgres"
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+)
+
+// checkCourseStatusTransition tells whether course may move to status, both for admins and for the scheduler.
+func checkCourseStatusTransition(ctx context.Context, courseRepo postgres.CourseRepo, course model.Course, status string, tx *sqlx.Tx) error {
+	allowed := false
+	for _, next := range model.CourseStatusTransitions[course.Status] {
+		if next == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return &error2.StatusTransitionErr{From: course.Status, To: status, Reason: "transition is not allowed"}
+	}
+	switch status {
+	case model.CourseStatusInitial:
+		if course.Size > 0 {
+			return &error2.StatusTransitionErr{From: course.Status, To: status, Reason: "students are already registered"}
+		}
+	case model.CourseStatusOngoing:
+		_, err := courseRepo.GetCourseSchedulesByCourseId(ctx, course.Id, tx)
+		if err != nil {
+			var notFoundErr *error2.ResourceNotFoundErr
+			if errors.As(err, &notFoundErr) {
+				return &error2.StatusTransitionErr{From: course.Status, To: status, Reason: "course has no schedule"}
+			}
+			return err
+		}
+	}
+	return nil
+}
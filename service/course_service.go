@@ -1,27 +1,44 @@
 package service
 
 import (
+	"SchoolManagement/dto"
 	error2 "SchoolManagement/error"
 	"SchoolManagement/middleware"
 	"SchoolManagement/model"
 	"SchoolManagement/repo"
 	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/golang-jwt/jwt"
 	"github.com/jmoiron/sqlx"
+	"log"
+	"sort"
+	"strings"
+	"time"
 )
 
 type CourseService interface {
 	CreateCourse(ctx context.Context, course model.Course) error
 	GetCourseById(ctx context.Context, id string) (model.Course, error)
-	UpdateCourse(ctx context.Context, course model.Course) error
+	UpdateCourse(ctx context.Context, course model.Course, reason string) error
 	DeleteCourseById(ctx context.Context, id string) error
 	RegisterStudentToCourse(ctx context.Context, courseRegistration model.CourseRegistration) error
 	UnregisterStudentFromCourse(ctx context.Context, courseId string, studentId string) error
 	AddCourseSchedule(ctx context.Context, schedule model.CourseSchedule) error
 	GetCourseSchedulesByCourseId(ctx context.Context, courseId string) ([]model.CourseSchedule, error)
+	GetCourseSessionsByCourseId(ctx context.Context, courseId string, from time.Time, to time.Time) ([]model.CourseSession, error)
 	DeleteCourseScheduleById(ctx context.Context, id string) error
-	GetCoursesByUserId(ctx context.Context, userId string, semester int, academicYear string) ([]model.Course, error)
+	GetCoursesByUserId(ctx context.Context, params dto.GetCoursesParams) ([]model.Course, dto.PageInfo, error)
+	JoinWaitlist(ctx context.Context, courseId string, studentId string) (model.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, courseId string, studentId string) error
+	AcceptWaitlistOffer(ctx context.Context, courseId string, studentId string) error
+	GetWaitlist(ctx context.Context, courseId string) ([]model.WaitlistEntry, error)
+	ExpireWaitlistOffers(ctx context.Context, now time.Time) error
+	GetCourseHistory(ctx context.Context, courseId string) ([]model.CourseStatusChange, error)
+	SearchCourses(ctx context.Context, params dto.SearchCoursesParams) ([]model.CatalogCourse, dto.PageInfo, error)
+	GetCourseRoster(ctx context.Context, courseId string) ([]model.Student, error)
 }
 
 type courseService struct {
@@ -29,6 +46,13 @@ type courseService struct {
 	transactionManager repo.TransactionManager
 	authMiddleware     middleware.AuthMiddleware
 	userRepo           postgres.UserRepo
+	subjectRepo        postgres.SubjectRepo
+	scoreRepo          postgres.ScoreRepo
+	roomRepo           postgres.RoomRepo
+	waitlistRepo       postgres.WaitlistRepo
+	termRepo           postgres.TermRepo
+	courseStatusRepo   postgres.CourseStatusRepo
+	offerDuration      time.Duration
 }
 
 func (c *courseService) CreateCourse(ctx context.Context, course model.Course) error {
@@ -36,15 +60,38 @@ func (c *courseService) CreateCourse(ctx context.Context, course model.Course) e
 	if err != nil {
 		return &error2.UnauthorizedErr{Message: "Required admin role to create course"}
 	}
+	_, err = c.termRepo.GetTermByCode(ctx, course.TermCode, nil)
+	if err != nil {
+		return err
+	}
 	course.Status = model.CourseStatusInitial
-	return c.courseRepo.CreateCourse(ctx, course, nil)
+	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		e := c.courseRepo.CreateCourse(ctx, course, tx)
+		if e != nil {
+			return e
+		}
+		return c.courseStatusRepo.InsertStatusChange(ctx, model.CourseStatusChange{
+			CourseId:  course.Id,
+			ToStatus:  model.CourseStatusInitial,
+			Actor:     actorFromContext(ctx),
+			Reason:    "course created",
+			CreatedAt: time.Now().Unix(),
+		}, tx)
+	})
+}
+
+func actorFromContext(ctx context.Context) string {
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	return claims["userId"].(string)
 }
 
 func (c *courseService) GetCourseById(ctx context.Context, id string) (model.Course, error) {
 	return c.courseRepo.GetCourseById(ctx, id, nil)
 }
 
-func (c *courseService) UpdateCourse(ctx context.Context, course model.Course) error {
+// UpdateCourse applies a partial update. A status change must follow model.CourseStatusTransitions and is recorded
+// in the course history with reason.
+func (c *courseService) UpdateCourse(ctx context.Context, course model.Course, reason string) error {
 	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
 	if err != nil {
 		return &error2.UnauthorizedErr{Message: "Required admin role to update course"}
@@ -52,7 +99,88 @@ func (c *courseService) UpdateCourse(ctx context.Context, course model.Course) e
 	if course.Status != "" && course.Status != model.CourseStatusInitial && course.Status != model.CourseStatusRegister && course.Status != model.CourseStatusOngoing && course.Status != model.CourseStatusComplete {
 		return &error2.InvalidInputErr{Message: "Course status must be Initial, Register, Ongoing or Complete"}
 	}
-	return c.courseRepo.UpdateCourse(ctx, course, nil)
+	if course.TermCode != "" {
+		_, err = c.termRepo.GetTermByCode(ctx, course.TermCode, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if course.Capacity != 0 {
+			// Schedules are locked before the course, in the same order as AddCourseSchedule and UpdateRoom.
+			err = c.courseRepo.LockCourseSchedules(ctx, tx)
+			if err != nil {
+				return err
+			}
+		}
+		existing, err := c.courseRepo.GetCourseForUpdate(ctx, course.Id, tx)
+		if err != nil {
+			return err
+		}
+		if course.Capacity != 0 && course.Capacity < existing.Size {
+			return &error2.InvalidInputErr{Message: fmt.Sprintf("capacity cannot be lower than the %d registered students", existing.Size)}
+		}
+		if course.Capacity > existing.Capacity {
+			err = c.checkScheduledRoomsCapacity(ctx, course.Id, course.Capacity, tx)
+			if err != nil {
+				return err
+			}
+		}
+		if course.Status == existing.Status {
+			course.Status = ""
+		}
+		if course.Status != "" {
+			err = checkCourseStatusTransition(ctx, c.courseRepo, existing, course.Status, tx)
+			if err != nil {
+				return err
+			}
+			if reason == "" {
+				reason = "updated by admin"
+			}
+			err = c.courseStatusRepo.InsertStatusChange(ctx, model.CourseStatusChange{
+				CourseId:   course.Id,
+				FromStatus: existing.Status,
+				ToStatus:   course.Status,
+				Actor:      actorFromContext(ctx),
+				Reason:     reason,
+				CreatedAt:  time.Now().Unix(),
+			}, tx)
+			if err != nil {
+				return err
+			}
+		}
+		err = c.courseRepo.UpdateCourse(ctx, course, tx)
+		if err != nil {
+			return err
+		}
+		// A larger capacity or a re-enabled waitlist may free seats for waitlisted students.
+		updatedCourse, err := c.courseRepo.GetCourseForUpdate(ctx, course.Id, tx)
+		if err != nil {
+			return err
+		}
+		if updatedCourse.Status != model.CourseStatusRegister {
+			return nil
+		}
+		return c.fillFreeSeats(ctx, updatedCourse, tx)
+	})
+}
+
+func (c *courseService) GetCourseHistory(ctx context.Context, courseId string) ([]model.CourseStatusChange, error) {
+	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleTeacher)
+	if err != nil {
+		return nil, &error2.UnauthorizedErr{Message: "Required admin or course teacher to view course history"}
+	}
+	course, err := c.courseRepo.GetCourseById(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["role"].(string) == model.RoleTeacher {
+		if claims["userId"].(string) != course.TeacherId {
+			return nil, &error2.UnauthorizedErr{Message: "Unauthorized"}
+		}
+	}
+	return c.courseStatusRepo.GetStatusChangesByCourseId(ctx, courseId, nil)
 }
 
 func (c *courseService) DeleteCourseById(ctx context.Context, id string) error {
@@ -79,25 +207,252 @@ func (c *courseService) RegisterStudentToCourse(ctx context.Context, courseRegis
 		if err != nil {
 			return err
 		}
-		if course.Status != model.CourseStatusRegister {
-			return error2.CourseRegisterTimoutErr
-		}
-		if course.Capacity-course.Size == 0 {
-			return error2.CourseLimitExceededErr
-		}
-		err = c.courseRepo.InsertCourseRegistration(ctx, courseRegistration, tx)
+		err = c.checkRegistrationOpen(ctx, course, tx)
 		if err != nil {
 			return err
 		}
-		course.Size += 1
-		err = c.courseRepo.UpdateCourse(ctx, course, tx)
+		err = c.checkSeatAvailable(ctx, course, courseRegistration.StudentId, tx)
 		if err != nil {
 			return err
 		}
-		return nil
+		return c.enrollStudent(ctx, course, courseRegistration, tx)
 	})
 }
 
+// checkRegistrationOpen lets students in while the course is in Register, up to the registration_close date of
+// its term. The course stays in Register until classes start, so the date is what closes registration.
+func (c *courseService) checkRegistrationOpen(ctx context.Context, course model.Course, tx *sqlx.Tx) error {
+	if course.Status != model.CourseStatusRegister {
+		return error2.CourseRegisterTimoutErr
+	}
+	term, err := c.termRepo.GetTermByCode(ctx, course.TermCode, tx)
+	if err != nil {
+		return err
+	}
+	if time.Now().Format(utils.DateLayout) > term.RegistrationClose {
+		return error2.CourseRegisterTimoutErr
+	}
+	return nil
+}
+
+// checkDropAllowed lets students leave a course up to the add_drop_deadline of its term, which may fall after
+// classes started.
+func (c *courseService) checkDropAllowed(ctx context.Context, course model.Course, tx *sqlx.Tx) error {
+	if course.Status != model.CourseStatusRegister && course.Status != model.CourseStatusOngoing {
+		return error2.CourseRegisterTimoutErr
+	}
+	term, err := c.termRepo.GetTermByCode(ctx, course.TermCode, tx)
+	if err != nil {
+		return err
+	}
+	if time.Now().Format(utils.DateLayout) > term.AddDropDeadline {
+		return error2.CourseRegisterTimoutErr
+	}
+	return nil
+}
+
+// checkSeatAvailable reports whether the student may take a seat now. Seats offered to waitlisted students are
+// held for them, and students still waiting ahead in the queue go first.
+func (c *courseService) checkSeatAvailable(ctx context.Context, course model.Course, studentId string, tx *sqlx.Tx) error {
+	entries, err := c.waitlistRepo.GetWaitlistByCourseId(ctx, course.Id, tx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	freeSeats := course.Capacity - course.Size
+	queuedAhead := false
+	for _, entry := range entries {
+		if entry.Status == model.WaitlistStatusOffered && entry.OfferExpiresAt > now {
+			if entry.StudentId == studentId {
+				return nil
+			}
+			freeSeats--
+			continue
+		}
+		if entry.StudentId == studentId {
+			break
+		}
+		if entry.Status == model.WaitlistStatusWaiting {
+			queuedAhead = true
+		}
+	}
+	if freeSeats <= 0 || queuedAhead {
+		return error2.CourseLimitExceededErr
+	}
+	return nil
+}
+
+func (c *courseService) enrollStudent(ctx context.Context, course model.Course, courseRegistration model.CourseRegistration, tx *sqlx.Tx) error {
+	err := c.checkPrerequisites(ctx, course, courseRegistration.StudentId, tx)
+	if err != nil {
+		return err
+	}
+	err = c.checkTimetableConflicts(ctx, course, courseRegistration.StudentId, tx)
+	if err != nil {
+		return err
+	}
+	err = c.courseRepo.InsertCourseRegistration(ctx, courseRegistration, tx)
+	if err != nil {
+		return err
+	}
+	// Only write the size, the other fields of course also hold values joined from the term.
+	err = c.courseRepo.UpdateCourse(ctx, model.Course{Id: course.Id, Size: course.Size + 1}, tx)
+	if err != nil {
+		return err
+	}
+	return c.waitlistRepo.DeleteWaitlistEntry(ctx, course.Id, courseRegistration.StudentId, tx)
+}
+
+// fillFreeSeats hands the free seats of a course to the head of its waitlist. Students are registered straight
+// away, or offered the seat when an offer duration is configured. Once registration closed, only the expired offers
+// are dropped. The caller must hold the course row lock.
+func (c *courseService) fillFreeSeats(ctx context.Context, course model.Course, tx *sqlx.Tx) error {
+	now := time.Now()
+	err := c.waitlistRepo.DeleteExpiredOffers(ctx, course.Id, now.Unix(), tx)
+	if err != nil {
+		return err
+	}
+	err = c.checkRegistrationOpen(ctx, course, tx)
+	if errors.Is(err, error2.CourseRegisterTimoutErr) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	entries, err := c.waitlistRepo.GetWaitlistByCourseId(ctx, course.Id, tx)
+	if err != nil {
+		return err
+	}
+	freeSeats := course.Capacity - course.Size
+	for _, entry := range entries {
+		if entry.Status == model.WaitlistStatusOffered {
+			freeSeats--
+		}
+	}
+	for _, entry := range entries {
+		if freeSeats <= 0 {
+			return nil
+		}
+		if entry.Status != model.WaitlistStatusWaiting {
+			continue
+		}
+		if c.offerDuration > 0 {
+			err = c.waitlistRepo.OfferWaitlistEntry(ctx, entry.Id, now.Add(c.offerDuration).Unix(), tx)
+			if err != nil {
+				return err
+			}
+			freeSeats--
+			continue
+		}
+		err = c.enrollStudent(ctx, course, model.CourseRegistration{CourseId: course.Id, StudentId: entry.StudentId}, tx)
+		if err != nil {
+			// Students who can no longer take the course lose their place instead of blocking the queue.
+			var missingPrerequisitesErr *error2.MissingPrerequisitesErr
+			var scheduleConflictErr *error2.ScheduleConflictErr
+			if errors.As(err, &missingPrerequisitesErr) || errors.As(err, &scheduleConflictErr) {
+				log.Println("Course service, drop waitlisted student ", entry.StudentId, " from course ", course.Id, ": ", err)
+				err = c.waitlistRepo.DeleteWaitlistEntry(ctx, course.Id, entry.StudentId, tx)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+		course.Size += 1
+		freeSeats--
+	}
+	return nil
+}
+
+func (c *courseService) checkPrerequisites(ctx context.Context, course model.Course, studentId string, tx *sqlx.Tx) error {
+	var notFoundErr *error2.ResourceNotFoundErr
+	prerequisites, err := c.subjectRepo.GetPrerequisitesBySubjectId(ctx, course.SubjectId, tx)
+	if err != nil {
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return err
+	}
+	passedSubjectIds, err := c.scoreRepo.GetPassedSubjectIdsByStudentId(ctx, studentId, tx)
+	if err != nil {
+		return err
+	}
+	passed := make(map[string]bool)
+	for _, subjectId := range passedSubjectIds {
+		passed[subjectId] = true
+	}
+	registeredCourses, err := c.courseRepo.GetCoursesByUserId(ctx, studentId, model.RoleStudent, course.TermCode, tx)
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return err
+	}
+	takingNow := make(map[string]bool)
+	for _, registeredCourse := range registeredCourses {
+		takingNow[registeredCourse.SubjectId] = true
+	}
+
+	var missing []string
+	for _, prerequisite := range prerequisites {
+		if passed[prerequisite.PrerequisiteId] {
+			continue
+		}
+		if prerequisite.Type == model.PrerequisiteTypeCorequisite {
+			if takingNow[prerequisite.PrerequisiteId] {
+				continue
+			}
+			missing = append(missing, fmt.Sprintf("%s (%s, corequisite)", prerequisite.PrerequisiteId, prerequisite.PrerequisiteName))
+		} else {
+			missing = append(missing, fmt.Sprintf("%s (%s)", prerequisite.PrerequisiteId, prerequisite.PrerequisiteName))
+		}
+	}
+	if len(missing) > 0 {
+		return &error2.MissingPrerequisitesErr{Subjects: missing}
+	}
+	return nil
+}
+
+func (c *courseService) checkTimetableConflicts(ctx context.Context, course model.Course, studentId string, tx *sqlx.Tx) error {
+	var notFoundErr *error2.ResourceNotFoundErr
+	schedules, err := c.courseRepo.GetCourseSchedulesByCourseId(ctx, course.Id, tx)
+	if err != nil {
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return err
+	}
+	studentSchedules, err := c.courseRepo.GetCourseSchedulesByStudentId(ctx, studentId, course.TermCode, tx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		for _, studentSchedule := range studentSchedules {
+			session, overlap, e := utils.FindScheduleOverlap(schedule, studentSchedule)
+			if e != nil {
+				return e
+			}
+			if overlap {
+				return &error2.ScheduleConflictErr{
+					Message: fmt.Sprintf("course %s clashes with registered course %s in room %s, %s",
+						course.Id, studentSchedule.CourseId, studentSchedule.Room, describeSession(studentSchedule, session)),
+					ScheduleIds: []int{studentSchedule.Id},
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func describeSession(schedule model.CourseSchedule, session model.CourseSession) string {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	start := time.Unix(session.StartTime, 0).In(location)
+	end := time.Unix(session.EndTime, 0).In(location)
+	return fmt.Sprintf("every %s %s-%s %s, first clash on %s",
+		start.Weekday(), start.Format(utils.TimeLayout), end.Format(utils.TimeLayout), schedule.Timezone, start.Format(utils.DateLayout))
+}
+
 func (c *courseService) UnregisterStudentFromCourse(ctx context.Context, courseId string, studentId string) error {
 	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleStudent)
 	if err != nil {
@@ -110,34 +465,368 @@ func (c *courseService) UnregisterStudentFromCourse(ctx context.Context, courseI
 		}
 	}
 	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
-		course, err := c.courseRepo.GetCourseById(ctx, courseId, tx)
+		course, err := c.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
 		if err != nil {
 			return err
 		}
-		if course.Status != model.CourseStatusRegister {
-			return error2.CourseRegisterTimoutErr
+		err = c.checkDropAllowed(ctx, course, tx)
+		if err != nil {
+			return err
+		}
+		_, err = c.courseRepo.GetCourseRegistration(ctx, courseId, studentId, tx)
+		if err != nil {
+			return err
 		}
 		err = c.courseRepo.DeleteCourseRegistration(ctx, courseId, studentId, tx)
 		if err != nil {
 			return err
 		}
 		err = c.courseRepo.DecreaseCourseSize(ctx, courseId, 1, tx)
+		if err != nil {
+			return err
+		}
+		course.Size -= 1
+		return c.fillFreeSeats(ctx, course, tx)
+	})
+}
+
+func (c *courseService) checkStudentOrAdmin(ctx context.Context, studentId string) error {
+	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleStudent)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin or student role to manage the waitlist"}
+	}
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["role"].(string) == model.RoleStudent {
+		if claims["userId"].(string) != studentId {
+			return &error2.UnauthorizedErr{Message: "Unauthorized"}
+		}
+	}
+	return nil
+}
+
+func (c *courseService) JoinWaitlist(ctx context.Context, courseId string, studentId string) (model.WaitlistEntry, error) {
+	err := c.checkStudentOrAdmin(ctx, studentId)
+	if err != nil {
+		return model.WaitlistEntry{}, err
+	}
+	var entry model.WaitlistEntry
+	err = c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		course, e := c.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+		if e != nil {
+			return e
+		}
+		e = c.checkRegistrationOpen(ctx, course, tx)
+		if e != nil {
+			return e
+		}
+		if course.WaitlistEnabled == nil || !*course.WaitlistEnabled {
+			return &error2.InvalidInputErr{Message: "course does not have a waitlist"}
+		}
+		_, e = c.courseRepo.GetCourseRegistration(ctx, courseId, studentId, tx)
+		if e == nil {
+			return &error2.UniqueConstraintErr{Message: "student already registered"}
+		}
+		var notFoundErr *error2.ResourceNotFoundErr
+		if !errors.As(e, &notFoundErr) {
+			return e
+		}
+		e = c.checkSeatAvailable(ctx, course, studentId, tx)
+		if e == nil {
+			return &error2.InvalidInputErr{Message: "course still has free seats, register directly"}
+		}
+		if !errors.Is(e, error2.CourseLimitExceededErr) {
+			return e
+		}
+		e = c.waitlistRepo.InsertWaitlistEntry(ctx, courseId, studentId, tx)
+		if e != nil {
+			return e
+		}
+		e = c.fillFreeSeats(ctx, course, tx)
+		if e != nil {
+			return e
+		}
+		entry, e = c.findWaitlistEntry(ctx, courseId, studentId, tx)
+		if errors.As(e, &notFoundErr) {
+			// The student was promoted straight away, an empty entry tells the caller so.
+			entry = model.WaitlistEntry{}
+			return nil
+		}
+		return e
+	})
+	return entry, err
+}
+
+func (c *courseService) findWaitlistEntry(ctx context.Context, courseId string, studentId string, tx *sqlx.Tx) (model.WaitlistEntry, error) {
+	entries, err := c.waitlistRepo.GetWaitlistByCourseId(ctx, courseId, tx)
+	if err != nil {
+		return model.WaitlistEntry{}, err
+	}
+	for _, entry := range entries {
+		if entry.StudentId == studentId {
+			return entry, nil
+		}
+	}
+	return model.WaitlistEntry{}, &error2.ResourceNotFoundErr{Resource: "waitlist entry"}
+}
+
+func (c *courseService) LeaveWaitlist(ctx context.Context, courseId string, studentId string) error {
+	err := c.checkStudentOrAdmin(ctx, studentId)
+	if err != nil {
+		return err
+	}
+	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		course, e := c.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+		if e != nil {
+			return e
+		}
+		_, e = c.waitlistRepo.GetWaitlistEntry(ctx, courseId, studentId, tx)
+		if e != nil {
+			return e
+		}
+		e = c.waitlistRepo.DeleteWaitlistEntry(ctx, courseId, studentId, tx)
+		if e != nil {
+			return e
+		}
+		if course.Status != model.CourseStatusRegister {
+			return nil
+		}
+		return c.fillFreeSeats(ctx, course, tx)
+	})
+}
+
+func (c *courseService) AcceptWaitlistOffer(ctx context.Context, courseId string, studentId string) error {
+	err := c.checkStudentOrAdmin(ctx, studentId)
+	if err != nil {
 		return err
+	}
+	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		course, e := c.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+		if e != nil {
+			return e
+		}
+		e = c.checkRegistrationOpen(ctx, course, tx)
+		if e != nil {
+			return e
+		}
+		entry, e := c.waitlistRepo.GetWaitlistEntry(ctx, courseId, studentId, tx)
+		if e != nil {
+			return e
+		}
+		if entry.Status != model.WaitlistStatusOffered || entry.OfferExpiresAt <= time.Now().Unix() {
+			return &error2.InvalidInputErr{Message: "student has no open waitlist offer"}
+		}
+		return c.enrollStudent(ctx, course, model.CourseRegistration{CourseId: courseId, StudentId: studentId}, tx)
 	})
 }
 
+// GetWaitlist returns the whole waitlist to admins and the course teacher, students only see their own entry.
+// Expired offers are settled by the writers and the course status scheduler, here they are only left out.
+func (c *courseService) GetWaitlist(ctx context.Context, courseId string) ([]model.WaitlistEntry, error) {
+	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleTeacher, model.RoleStudent)
+	if err != nil {
+		return nil, &error2.UnauthorizedErr{Message: "Unauthorized"}
+	}
+	course, err := c.courseRepo.GetCourseById(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["role"].(string) == model.RoleTeacher {
+		if claims["userId"].(string) != course.TeacherId {
+			return nil, &error2.UnauthorizedErr{Message: "Unauthorized"}
+		}
+	}
+	allEntries, err := c.waitlistRepo.GetWaitlistByCourseId(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	position := 0
+	var entries []model.WaitlistEntry
+	for _, entry := range allEntries {
+		if entry.Status == model.WaitlistStatusOffered && entry.OfferExpiresAt <= now {
+			continue
+		}
+		position++
+		entry.Position = position
+		if claims["role"].(string) == model.RoleStudent && entry.StudentId != claims["userId"].(string) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "waitlist"}
+	}
+	return entries, nil
+}
+
+// ExpireWaitlistOffers drops the offers that ran out and hands their seats to the next students in line. It runs
+// without a user in ctx, from the course status scheduler.
+func (c *courseService) ExpireWaitlistOffers(ctx context.Context, now time.Time) error {
+	courseIds, err := c.waitlistRepo.GetCourseIdsWithExpiredOffers(ctx, now.Unix(), nil)
+	if err != nil {
+		return err
+	}
+	// Settle each course on its own so a failing course does not hold back the others.
+	for _, courseId := range courseIds {
+		err = c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			course, e := c.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+			if e != nil {
+				return e
+			}
+			return c.fillFreeSeats(ctx, course, tx)
+		})
+		if err != nil {
+			log.Println("Course service, expire waitlist offers of course ", courseId, " err: ", err)
+		}
+	}
+	return nil
+}
+
 func (c *courseService) AddCourseSchedule(ctx context.Context, schedule model.CourseSchedule) error {
 	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
 	if err != nil {
 		return &error2.UnauthorizedErr{Message: "Required admin role to add course schedule"}
 	}
-	return c.courseRepo.AddCourseSchedule(ctx, schedule, nil)
+	err = utils.ValidateCourseSchedule(schedule)
+	if err != nil {
+		return &error2.InvalidInputErr{Message: err.Error()}
+	}
+	// Widen the date range by a day on each side so patterns in other timezones are not missed.
+	from, to, err := utils.ScheduleDateRange(schedule)
+	if err != nil {
+		return err
+	}
+	startDate := from.AddDate(0, 0, -1).Format(utils.DateLayout)
+	endDate := to.Format(utils.DateLayout)
+	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		e := c.courseRepo.LockCourseSchedules(ctx, tx)
+		if e != nil {
+			return e
+		}
+		course, e := c.courseRepo.GetCourseById(ctx, schedule.CourseId, tx)
+		if e != nil {
+			return e
+		}
+		room, e := c.roomRepo.GetRoomById(ctx, schedule.Room, tx)
+		if e != nil {
+			return e
+		}
+		if room.Capacity < course.Capacity {
+			return &error2.InvalidInputErr{Message: fmt.Sprintf("room %s holds %d students but the course needs %d", room.Id, room.Capacity, course.Capacity)}
+		}
+		roomSchedules, e := c.courseRepo.GetSchedulesByRoomInDateRange(ctx, schedule.Room, startDate, endDate, tx)
+		if e != nil {
+			return e
+		}
+		roomSchedules, e = filterOverlappingSchedules(schedule, roomSchedules)
+		if e != nil {
+			return e
+		}
+		teacherSchedules, e := c.courseRepo.GetSchedulesByTeacherIdInDateRange(ctx, course.TeacherId, startDate, endDate, tx)
+		if e != nil {
+			return e
+		}
+		teacherSchedules, e = filterOverlappingSchedules(schedule, teacherSchedules)
+		if e != nil {
+			return e
+		}
+		if len(roomSchedules) > 0 || len(teacherSchedules) > 0 {
+			return newDoubleBookingErr(schedule.Room, roomSchedules, course.TeacherId, teacherSchedules)
+		}
+		return c.courseRepo.AddCourseSchedule(ctx, schedule, tx)
+	})
+}
+
+// checkScheduledRoomsCapacity checks that every room the course is scheduled in holds capacity students.
+func (c *courseService) checkScheduledRoomsCapacity(ctx context.Context, courseId string, capacity int, tx *sqlx.Tx) error {
+	room, err := c.roomRepo.GetSmallestRoomByCourseId(ctx, courseId, tx)
+	var notFoundErr *error2.ResourceNotFoundErr
+	if errors.As(err, &notFoundErr) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if room.Capacity < capacity {
+		return &error2.InvalidInputErr{Message: fmt.Sprintf("room %s holds %d students but the course needs %d", room.Id, room.Capacity, capacity)}
+	}
+	return nil
+}
+
+func filterOverlappingSchedules(schedule model.CourseSchedule, candidates []model.CourseSchedule) ([]model.CourseSchedule, error) {
+	var overlapping []model.CourseSchedule
+	for _, candidate := range candidates {
+		_, overlap, err := utils.FindScheduleOverlap(schedule, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if overlap {
+			overlapping = append(overlapping, candidate)
+		}
+	}
+	return overlapping, nil
+}
+
+func newDoubleBookingErr(room string, roomSchedules []model.CourseSchedule, teacherId string, teacherSchedules []model.CourseSchedule) error {
+	var messages []string
+	var scheduleIds []int
+	seen := make(map[int]bool)
+	collect := func(schedules []model.CourseSchedule) []int {
+		var ids []int
+		for _, schedule := range schedules {
+			ids = append(ids, schedule.Id)
+			if !seen[schedule.Id] {
+				seen[schedule.Id] = true
+				scheduleIds = append(scheduleIds, schedule.Id)
+			}
+		}
+		return ids
+	}
+	if len(roomSchedules) > 0 {
+		messages = append(messages, fmt.Sprintf("room %s is already booked by schedules %v", room, collect(roomSchedules)))
+	}
+	if len(teacherSchedules) > 0 {
+		messages = append(messages, fmt.Sprintf("teacher %s is already teaching in schedules %v", teacherId, collect(teacherSchedules)))
+	}
+	return &error2.ScheduleConflictErr{Message: strings.Join(messages, "; "), ScheduleIds: scheduleIds}
 }
 
 func (c *courseService) GetCourseSchedulesByCourseId(ctx context.Context, courseId string) ([]model.CourseSchedule, error) {
 	return c.courseRepo.GetCourseSchedulesByCourseId(ctx, courseId, nil)
 }
 
+// GetCourseSessionsByCourseId expands every schedule of the course into sessions within [from, to).
+// A zero from or to leaves that side of the range bounded only by the schedules themselves.
+func (c *courseService) GetCourseSessionsByCourseId(ctx context.Context, courseId string, from time.Time, to time.Time) ([]model.CourseSession, error) {
+	schedules, err := c.courseRepo.GetCourseSchedulesByCourseId(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []model.CourseSession
+	for _, schedule := range schedules {
+		scheduleFrom, scheduleTo, e := utils.ScheduleDateRange(schedule)
+		if e != nil {
+			return nil, e
+		}
+		if !from.IsZero() {
+			scheduleFrom = from
+		}
+		if !to.IsZero() {
+			scheduleTo = to
+		}
+		scheduleSessions, e := utils.ExpandCourseSchedule(schedule, scheduleFrom, scheduleTo)
+		if e != nil {
+			return nil, e
+		}
+		sessions = append(sessions, scheduleSessions...)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartTime < sessions[j].StartTime
+	})
+	return sessions, nil
+}
+
 func (c *courseService) DeleteCourseScheduleById(ctx context.Context, id string) error {
 	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
 	if err != nil {
@@ -146,26 +835,128 @@ func (c *courseService) DeleteCourseScheduleById(ctx context.Context, id string)
 	return c.courseRepo.DeleteCourseScheduleById(ctx, id, nil)
 }
 
-func (c *courseService) GetCoursesByUserId(ctx context.Context, userId string, semester int, academicYear string) ([]model.Course, error) {
+// GetCoursesByUserId lists the courses of a user in the given term, or in the current term when no term is given.
+// A limit of 0 returns every course on one page.
+func (c *courseService) GetCoursesByUserId(ctx context.Context, params dto.GetCoursesParams) ([]model.Course, dto.PageInfo, error) {
 	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
 	role := claims["role"].(string)
 	if role != model.RoleAdmin {
-		if claims["userId"].(string) != userId {
-			return nil, &error2.UnauthorizedErr{Message: "Unauthorized"}
+		if claims["userId"].(string) != params.UserId {
+			return nil, dto.PageInfo{}, &error2.UnauthorizedErr{Message: "Unauthorized"}
 		}
 	}
-	userInfo, err := c.userRepo.GetUserById(ctx, userId, nil)
+	userInfo, err := c.userRepo.GetUserById(ctx, params.UserId, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	if params.TermCode == "" {
+		currentTerm, e := c.termRepo.GetCurrentTerm(ctx, nil)
+		if e != nil {
+			return nil, dto.PageInfo{}, e
+		}
+		params.TermCode = currentTerm.Code
+	}
+	cursor, err := utils.DecodeCursor(params.Cursor, "id", "")
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	limit := params.Limit
+	params.Limit++
+	courses, err := c.courseRepo.GetCourseListByUserId(ctx, params, userInfo.Role, cursor, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	courses, info := utils.BuildKeysetPage(courses, limit, cursor, func(course model.Course) dto.Cursor {
+		return dto.Cursor{SortBy: "id", Value: course.Id, Id: course.Id}
+	})
+	if params.IncludeTotal {
+		total, e := c.courseRepo.CountCoursesByUserId(ctx, params, userInfo.Role, nil)
+		if e != nil {
+			return nil, dto.PageInfo{}, e
+		}
+		info.Total = &total
+	}
+	return courses, info, nil
+}
+
+// SearchCourses browses the course catalog and attaches the weekly schedules of each course found.
+func (c *courseService) SearchCourses(ctx context.Context, params dto.SearchCoursesParams) ([]model.CatalogCourse, dto.PageInfo, error) {
+	if params.From != "" && params.To != "" && params.From >= params.To {
+		return nil, dto.PageInfo{}, &error2.InvalidInputErr{Message: "from must be before to"}
+	}
+	cursor, err := utils.DecodeCursor(params.Cursor, "id", "")
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	limit := params.Limit
+	params.Limit++
+	courses, err := c.courseRepo.SearchCourses(ctx, params, cursor, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	courses, info := utils.BuildKeysetPage(courses, limit, cursor, func(course model.CatalogCourse) dto.Cursor {
+		return dto.Cursor{SortBy: "id", Value: course.Id, Id: course.Id}
+	})
+	if params.IncludeTotal {
+		total, e := c.courseRepo.CountSearchCourses(ctx, params, nil)
+		if e != nil {
+			return nil, dto.PageInfo{}, e
+		}
+		info.Total = &total
+	}
+	if len(courses) == 0 {
+		return courses, info, nil
+	}
+
+	courseIds := make([]string, len(courses))
+	for i, course := range courses {
+		courseIds[i] = course.Id
+	}
+	schedules, err := c.courseRepo.GetSchedulesByCourseIds(ctx, courseIds, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	schedulesByCourse := make(map[string][]model.CourseSchedule)
+	for _, schedule := range schedules {
+		schedulesByCourse[schedule.CourseId] = append(schedulesByCourse[schedule.CourseId], schedule)
+	}
+	for i := range courses {
+		courses[i].Schedules = schedulesByCourse[courses[i].Id]
+	}
+	return courses, info, nil
+}
+
+// GetCourseRoster lists the students registered to a course, for admins and the course teacher.
+func (c *courseService) GetCourseRoster(ctx context.Context, courseId string) ([]model.Student, error) {
+	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleTeacher)
+	if err != nil {
+		return nil, &error2.UnauthorizedErr{Message: "Required admin or course teacher to view the roster"}
+	}
+	course, err := c.courseRepo.GetCourseById(ctx, courseId, nil)
 	if err != nil {
 		return nil, err
 	}
-	return c.courseRepo.GetCoursesByUserId(ctx, userId, userInfo.Role, semester, academicYear, nil)
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["role"].(string) == model.RoleTeacher {
+		if claims["userId"].(string) != course.TeacherId {
+			return nil, &error2.UnauthorizedErr{Message: "Unauthorized"}
+		}
+	}
+	return c.courseRepo.GetStudentsByCourseId(ctx, courseId, nil)
 }
 
-func NewCourseService(courseRepo postgres.CourseRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware, userRepo postgres.UserRepo) CourseService {
+func NewCourseService(courseRepo postgres.CourseRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware, userRepo postgres.UserRepo, subjectRepo postgres.SubjectRepo, scoreRepo postgres.ScoreRepo, roomRepo postgres.RoomRepo, waitlistRepo postgres.WaitlistRepo, termRepo postgres.TermRepo, courseStatusRepo postgres.CourseStatusRepo, offerDuration time.Duration) CourseService {
 	return &courseService{
 		courseRepo:         courseRepo,
 		transactionManager: transactionManager,
 		authMiddleware:     authMiddleware,
 		userRepo:           userRepo,
+		subjectRepo:        subjectRepo,
+		scoreRepo:          scoreRepo,
+		roomRepo:           roomRepo,
+		waitlistRepo:       waitlistRepo,
+		termRepo:           termRepo,
+		courseStatusRepo:   courseStatusRepo,
+		offerDuration:      offerDuration,
 	}
 }
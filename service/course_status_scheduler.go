@@ -0,0 +1,229 @@
+package service
+
+import (
+	"SchoolManagement/dto"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"log"
+	"time"
+)
+
+// CourseStatusScheduler moves courses through Initial, Register, Ongoing and Complete following their term dates:
+// registration opens on registration_open, classes start on start_date and the course completes after end_date.
+// Courses stay in Register until classes start: registration_close and add_drop_deadline are enforced by the course
+// service on each registration and drop, not by a status. Each run also settles the waitlist offers that expired.
+type CourseStatusScheduler interface {
+	Start(ctx context.Context, interval time.Duration)
+	PreviewTransitions(ctx context.Context, date time.Time) ([]model.CourseStatusChange, error)
+	GetTransitionLog(ctx context.Context, params dto.PaginationParams) ([]model.CourseStatusChange, error)
+}
+
+type courseStatusScheduler struct {
+	courseService      CourseService
+	courseRepo         postgres.CourseRepo
+	courseStatusRepo   postgres.CourseStatusRepo
+	transactionManager repo.TransactionManager
+	authMiddleware     middleware.AuthMiddleware
+}
+
+// Start runs the scheduler in the background until ctx is cancelled, a non-positive interval disables it.
+func (c *courseStatusScheduler) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		log.Println("Course status scheduler disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			now := time.Now()
+			err := c.runOnce(ctx, now)
+			if err != nil {
+				log.Println("Course status scheduler, run err: ", err)
+			}
+			err = c.courseService.ExpireWaitlistOffers(ctx, now)
+			if err != nil {
+				log.Println("Course status scheduler, expire waitlist offers err: ", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *courseStatusScheduler) runOnce(ctx context.Context, now time.Time) error {
+	changes, err := c.planTransitions(ctx, now)
+	if err != nil {
+		return err
+	}
+	// Apply each course on its own so a failing course does not hold back the others.
+	for start := 0; start < len(changes); {
+		end := start
+		for end < len(changes) && changes[end].CourseId == changes[start].CourseId {
+			end++
+		}
+		err = c.applyTransitions(ctx, changes[start:end])
+		if err != nil {
+			log.Println("Course status scheduler, update course ", changes[start].CourseId, " err: ", err)
+		}
+		start = end
+	}
+	return nil
+}
+
+func (c *courseStatusScheduler) applyTransitions(ctx context.Context, changes []model.CourseStatusChange) error {
+	courseId := changes[0].CourseId
+	return c.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		course, e := c.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+		if e != nil {
+			return e
+		}
+		// The course changed since it was planned, it is picked up again on the next run.
+		if course.Status != changes[0].FromStatus {
+			return nil
+		}
+		for _, change := range changes {
+			e = checkCourseStatusTransition(ctx, c.courseRepo, course, change.ToStatus, tx)
+			if e != nil {
+				// Keep the steps taken so far, the blocked one is retried on the next run.
+				log.Println("Course status scheduler, course ", courseId, " err: ", e)
+				break
+			}
+			e = c.courseStatusRepo.InsertStatusChange(ctx, change, tx)
+			if e != nil {
+				return e
+			}
+			log.Println("Course status scheduler, course ", courseId, ": ", change.FromStatus, " -> ", change.ToStatus, ", ", change.Reason)
+			course.Status = change.ToStatus
+		}
+		if course.Status == changes[0].FromStatus {
+			return nil
+		}
+		return c.courseRepo.UpdateCourse(ctx, model.Course{Id: courseId, Status: course.Status}, tx)
+	})
+}
+
+func (c *courseStatusScheduler) planTransitions(ctx context.Context, now time.Time) ([]model.CourseStatusChange, error) {
+	calendars, err := c.courseStatusRepo.GetUnfinishedCourseCalendars(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	today := now.Format(utils.DateLayout)
+	var changes []model.CourseStatusChange
+	for _, calendar := range calendars {
+		changes = append(changes, planCourseTransitions(calendar, today, now.Unix())...)
+	}
+	return changes, nil
+}
+
+// planCourseTransitions returns the steps a course has to take to catch up with its term calendar on today.
+// Dates are compared as YYYY-MM-DD strings.
+func planCourseTransitions(calendar model.CourseCalendar, today string, createdAt int64) []model.CourseStatusChange {
+	steps := []struct {
+		from   string
+		to     string
+		due    bool
+		reason string
+	}{
+		{model.CourseStatusInitial, model.CourseStatusRegister, today >= calendar.RegistrationOpen, "registration opened on " + calendar.RegistrationOpen},
+		{model.CourseStatusRegister, model.CourseStatusOngoing, today >= calendar.StartDate, "classes started on " + calendar.StartDate},
+		{model.CourseStatusOngoing, model.CourseStatusComplete, today > calendar.EndDate, "term ended on " + calendar.EndDate},
+	}
+	var changes []model.CourseStatusChange
+	status := calendar.Status
+	for _, step := range steps {
+		if status != step.from || !step.due {
+			continue
+		}
+		changes = append(changes, model.CourseStatusChange{
+			CourseId:   calendar.CourseId,
+			FromStatus: step.from,
+			ToStatus:   step.to,
+			Actor:      model.CourseStatusActorScheduler,
+			Reason:     fmt.Sprintf("term %s: %s", calendar.TermCode, step.reason),
+			CreatedAt:  createdAt,
+		})
+		status = step.to
+	}
+	return changes
+}
+
+func (c *courseStatusScheduler) PreviewTransitions(ctx context.Context, date time.Time) ([]model.CourseStatusChange, error) {
+	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, &error2.UnauthorizedErr{Message: "Required admin role to preview course status changes"}
+	}
+	changes, err := c.planTransitions(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return c.checkTransitions(ctx, changes)
+}
+
+// checkTransitions runs the guards of applyTransitions without writing anything. A change that would be rejected
+// gets a BlockedReason and the later changes of its course are left out, as the scheduler would stop there.
+func (c *courseStatusScheduler) checkTransitions(ctx context.Context, changes []model.CourseStatusChange) ([]model.CourseStatusChange, error) {
+	var checked []model.CourseStatusChange
+	var course model.Course
+	blocked := false
+	for _, change := range changes {
+		if course.Id != change.CourseId {
+			var err error
+			course, err = c.courseRepo.GetCourseById(ctx, change.CourseId, nil)
+			var notFoundErr *error2.ResourceNotFoundErr
+			if errors.As(err, &notFoundErr) {
+				// Deleted since it was planned.
+				course = model.Course{Id: change.CourseId}
+				blocked = true
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			blocked = false
+		}
+		if blocked {
+			continue
+		}
+		err := checkCourseStatusTransition(ctx, c.courseRepo, course, change.ToStatus, nil)
+		var statusTransitionErr *error2.StatusTransitionErr
+		if errors.As(err, &statusTransitionErr) {
+			change.BlockedReason = statusTransitionErr.Reason
+			blocked = true
+		} else if err != nil {
+			return nil, err
+		}
+		checked = append(checked, change)
+		course.Status = change.ToStatus
+	}
+	return checked, nil
+}
+
+func (c *courseStatusScheduler) GetTransitionLog(ctx context.Context, params dto.PaginationParams) ([]model.CourseStatusChange, error) {
+	err := c.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, &error2.UnauthorizedErr{Message: "Required admin role to view course status changes"}
+	}
+	return c.courseStatusRepo.GetStatusChanges(ctx, model.CourseStatusActorScheduler, params, nil)
+}
+
+func NewCourseStatusScheduler(courseService CourseService, courseRepo postgres.CourseRepo, courseStatusRepo postgres.CourseStatusRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware) CourseStatusScheduler {
+	return &courseStatusScheduler{
+		courseService:      courseService,
+		courseRepo:         courseRepo,
+		courseStatusRepo:   courseStatusRepo,
+		transactionManager: transactionManager,
+		authMiddleware:     authMiddleware,
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/repo/redis"
+	"SchoolManagement/utils"
+	"context"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+// dummyPasswordHash is compared against when the submitted id does not exist, so that the response takes as long
+// as for a wrong password.
+var dummyPasswordHash = func() string {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return string(hash)
+}()
+
+func clientIpFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(middleware.ClientIpContextKey).(string)
+	return ip
+}
+
+// loginThrottle counts failed logins and failed second factor checks per account and per IP address, and records
+// the auth events. It is shared by the login, the two-factor settings and the password change of the logged in user.
+type loginThrottle struct {
+	loginAttemptStore redis.LoginAttemptStore
+	authEventRepo     postgres.AuthEventRepo
+	accountPolicy     utils.LoginThrottlePolicy
+	ipPolicy          utils.LoginThrottlePolicy
+}
+
+func (a *loginThrottle) isLoginBlocked(ctx context.Context, id string, ip string) (bool, error) {
+	blockedFor, err := a.loginAttemptStore.GetBlockedFor(ctx, redis.LoginScopeAccount, id)
+	if err != nil || blockedFor > 0 {
+		return blockedFor > 0, err
+	}
+	blockedFor, err = a.loginAttemptStore.GetBlockedFor(ctx, redis.LoginScopeIp, ip)
+	return blockedFor > 0, err
+}
+
+// checkNotBlocked is for checks of the logged in user, such as their current password or a second factor code, which
+// count against the same limits as the login.
+func (a *loginThrottle) checkNotBlocked(ctx context.Context, userId string, ip string) error {
+	blocked, err := a.isLoginBlocked(ctx, userId, ip)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		a.recordAuthEvent(ctx, userId, ip, model.AuthEventLoginBlocked)
+		return error2.TooManyLoginAttemptsErr
+	}
+	return nil
+}
+
+// recordLoginFailure counts the failure against both the account and the IP address and blocks whichever of them
+// has failed too often.
+func (a *loginThrottle) recordLoginFailure(ctx context.Context, id string, ip string) error {
+	err := a.throttle(ctx, redis.LoginScopeAccount, id, a.accountPolicy)
+	if err != nil {
+		return err
+	}
+	return a.throttle(ctx, redis.LoginScopeIp, ip, a.ipPolicy)
+}
+
+func (a *loginThrottle) throttle(ctx context.Context, scope string, subject string, policy utils.LoginThrottlePolicy) error {
+	failures, err := a.loginAttemptStore.RecordFailure(ctx, scope, subject, policy.Lockout)
+	if err != nil {
+		return err
+	}
+	delay := policy.Delay(failures)
+	if delay == 0 {
+		return nil
+	}
+	return a.loginAttemptStore.Block(ctx, scope, subject, delay)
+}
+
+// recordTwoFactorFailure records a wrong second factor code, which counts as a failed login.
+func (a *loginThrottle) recordTwoFactorFailure(ctx context.Context, userId string, ip string) error {
+	a.recordAuthEvent(ctx, userId, ip, model.AuthEventTwoFactorFailed)
+	return a.recordLoginFailure(ctx, userId, ip)
+}
+
+// recordPasswordChangeFailure records a wrong current password, which counts as a failed login.
+func (a *loginThrottle) recordPasswordChangeFailure(ctx context.Context, userId string, ip string) error {
+	a.recordAuthEvent(ctx, userId, ip, model.AuthEventPasswordChangeFailed)
+	return a.recordLoginFailure(ctx, userId, ip)
+}
+
+// recordAuthEvent only logs when the event cannot be stored, the login itself must not fail because of it.
+func (a *loginThrottle) recordAuthEvent(ctx context.Context, userId string, ip string, event string) {
+	_ = a.authEventRepo.InsertAuthEvent(ctx, model.AuthEvent{
+		UserId:    userId,
+		IpAddress: ip,
+		Event:     event,
+		CreatedAt: time.Now().Unix(),
+	}, nil)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"SchoolManagement/dto/response"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"SchoolManagement/utils"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"log"
+	"strings"
+	"time"
+)
+
+const oidcStateDuration = 10 * time.Minute
+
+var (
+	oidcNotConfiguredErr = &error2.InvalidInputErr{Message: "Single sign-on is not configured"}
+	oidcFailedErr        = &error2.UnauthorizedErr{Message: "Single sign-on failed"}
+	oidcNoAccountErr     = &error2.UnauthorizedErr{Message: "No account matches this single sign-on identity"}
+)
+
+// StartOidcLogin returns the URL of the provider to send the browser to. The state, the nonce and the PKCE code
+// verifier of the login are kept until the provider redirects back to CompleteOidcLogin. The browser binding has to
+// be set as a cookie, so that the callback only completes in the browser that started the login.
+func (a *authService) StartOidcLogin(ctx context.Context) (response.OidcLoginResponse, error) {
+	if !a.oidcConfig.Enabled() {
+		return response.OidcLoginResponse{}, oidcNotConfiguredErr
+	}
+	state, err := utils.GenerateToken()
+	if err != nil {
+		return response.OidcLoginResponse{}, err
+	}
+	browserBinding, err := utils.GenerateToken()
+	if err != nil {
+		return response.OidcLoginResponse{}, err
+	}
+	loginState := model.OidcLoginState{BrowserBindingHash: utils.HashToken(browserBinding)}
+	loginState.Nonce, err = utils.GenerateToken()
+	if err != nil {
+		return response.OidcLoginResponse{}, err
+	}
+	loginState.CodeVerifier, err = utils.GenerateToken()
+	if err != nil {
+		return response.OidcLoginResponse{}, err
+	}
+	authorizationUrl, err := a.oidcClient.AuthorizationUrl(ctx, state, loginState.Nonce, loginState.CodeVerifier)
+	if err != nil {
+		return response.OidcLoginResponse{}, err
+	}
+	err = a.oidcStateStore.SaveState(ctx, utils.HashToken(state), loginState, oidcStateDuration)
+	if err != nil {
+		return response.OidcLoginResponse{}, err
+	}
+	return response.OidcLoginResponse{
+		AuthorizationUrl: authorizationUrl,
+		BrowserBinding:   browserBinding,
+		SecureCookie:     strings.HasPrefix(a.oidcConfig.RedirectUrl, "https://"),
+	}, nil
+}
+
+// CompleteOidcLogin redeems the code the provider redirected back with and logs in the user its ID token maps to.
+// Two-factor authentication still applies as for a password login.
+func (a *authService) CompleteOidcLogin(ctx context.Context, code string, state string, browserBinding string) (response.LoginResponse, error) {
+	if !a.oidcConfig.Enabled() {
+		return response.LoginResponse{}, oidcNotConfiguredErr
+	}
+	ip := clientIpFromContext(ctx)
+	loginState, found, err := a.oidcStateStore.ConsumeState(ctx, utils.HashToken(state))
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !found {
+		return response.LoginResponse{}, &error2.UnauthorizedErr{Message: "Invalid or expired single sign-on state"}
+	}
+	// Without the cookie, a code and state taken from another browser could be redeemed here, or a victim could be
+	// logged in to the attacker's account.
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(browserBinding)), []byte(loginState.BrowserBindingHash)) != 1 {
+		return response.LoginResponse{}, &error2.UnauthorizedErr{Message: "Single sign-on was not started from this browser"}
+	}
+	rawIdToken, err := a.oidcClient.ExchangeCode(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		log.Println("Auth service, oidc code exchange err :", err)
+		return response.LoginResponse{}, oidcFailedErr
+	}
+	claims, err := a.oidcClient.VerifyIdToken(ctx, rawIdToken, loginState.Nonce)
+	if err != nil {
+		log.Println("Auth service, oidc id token err :", err)
+		return response.LoginResponse{}, oidcFailedErr
+	}
+	user, err := a.findOidcUser(ctx, claims)
+	if err != nil {
+		a.recordAuthEvent(ctx, oidcEventSubject(claims), ip, model.AuthEventSsoLoginFailed)
+		return response.LoginResponse{}, err
+	}
+	blocked, err := a.isLoginBlocked(ctx, user.Id, ip)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if blocked {
+		a.recordAuthEvent(ctx, user.Id, ip, model.AuthEventLoginBlocked)
+		return response.LoginResponse{}, error2.TooManyLoginAttemptsErr
+	}
+	challenge, err := a.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if challenge != nil {
+		a.recordAuthEvent(ctx, user.Id, ip, model.AuthEventTwoFactorChallenged)
+		return a.issueChallenge(ctx, *challenge)
+	}
+	return a.completeLogin(ctx, user, ip)
+}
+
+// findOidcUser maps the identity to a user: by the identity linked on an earlier login, else by email, linking the
+// identity to the user found. Unknown users of a provisioning domain are created as students.
+func (a *authService) findOidcUser(ctx context.Context, claims model.OidcClaims) (model.User, error) {
+	var notFoundErr *error2.ResourceNotFoundErr
+	identity, err := a.userIdentityRepo.GetUserIdentity(ctx, claims.Issuer, claims.Subject, nil)
+	if err == nil {
+		user, e := a.userRepo.GetUserById(ctx, identity.UserId, nil)
+		if errors.As(e, &notFoundErr) {
+			return model.User{}, oidcNoAccountErr
+		}
+		return user, e
+	}
+	if !errors.As(err, &notFoundErr) {
+		return model.User{}, err
+	}
+	if claims.Email == "" || (!claims.EmailVerified && !a.oidcConfig.TrustUnverifiedEmail) {
+		return model.User{}, oidcNoAccountErr
+	}
+	user, err := a.userRepo.GetUserByEmail(ctx, claims.Email, nil)
+	if err != nil {
+		if !errors.As(err, &notFoundErr) {
+			return model.User{}, err
+		}
+		if !a.oidcConfig.CanProvision(claims.Email) {
+			return model.User{}, oidcNoAccountErr
+		}
+		return a.provisionOidcStudent(ctx, claims)
+	}
+	err = a.userIdentityRepo.InsertUserIdentity(ctx, oidcUserIdentity(claims, user.Id), nil)
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// provisionOidcStudent creates a student with the email as id and no password, so that it can only log in through
+// the provider until a password is set. The date of birth is required by the student records, so the provider has
+// to release the birthdate claim.
+func (a *authService) provisionOidcStudent(ctx context.Context, claims model.OidcClaims) (model.User, error) {
+	_, err := time.Parse(time.DateOnly, claims.Birthdate)
+	if err != nil {
+		return model.User{}, &error2.UnauthorizedErr{Message: "Single sign-on identity has no birthdate to create an account with"}
+	}
+	email := strings.ToLower(claims.Email)
+	name := claims.Name
+	if name == "" {
+		name = email
+	}
+	student := model.Student{User: model.User{
+		Id:          email,
+		Name:        name,
+		DateOfBirth: claims.Birthdate,
+		Email:       email,
+		Role:        model.RoleStudent,
+	}}
+	err = a.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		e := a.userRepo.InsertUser(ctx, student.User, tx)
+		if e != nil {
+			return e
+		}
+		e = a.studentRepo.InsertStudent(ctx, student, tx)
+		if e != nil {
+			return e
+		}
+		return a.userIdentityRepo.InsertUserIdentity(ctx, oidcUserIdentity(claims, student.Id), tx)
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	a.recordAuthEvent(ctx, student.Id, clientIpFromContext(ctx), model.AuthEventUserProvisioned)
+	return student.User, nil
+}
+
+func oidcUserIdentity(claims model.OidcClaims, userId string) model.UserIdentity {
+	return model.UserIdentity{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		UserId:    userId,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// oidcEventSubject is what a failed single sign-on is recorded under when it matched no user.
+func oidcEventSubject(claims model.OidcClaims) string {
+	if claims.Email != "" {
+		return claims.Email
+	}
+	return claims.Subject
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/repo/redis"
+	"SchoolManagement/utils"
+	"context"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/crypto/bcrypt"
+	"log"
+	"strings"
+	"time"
+)
+
+type PasswordService interface {
+	ChangePassword(ctx context.Context, currentPassword string, newPassword string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+}
+
+type passwordService struct {
+	loginThrottle
+	userRepo           postgres.UserRepo
+	passwordResetStore redis.PasswordResetStore
+	sessionStore       redis.SessionStore
+	refreshTokenStore  redis.RefreshTokenStore
+	mailSender         utils.MailSender
+	resetDuration      time.Duration
+	resetLimit         utils.PasswordResetLimit
+}
+
+// ChangePassword replaces the password of the logged in user once the current one is confirmed. Every session of
+// the user, including the current one, is revoked afterwards. Wrong current passwords count as failed logins, so
+// that a stolen access token cannot be used to guess the password.
+func (p *passwordService) ChangePassword(ctx context.Context, currentPassword string, newPassword string) error {
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	userId := claims["userId"].(string)
+	ip := clientIpFromContext(ctx)
+	err := p.checkNotBlocked(ctx, userId, ip)
+	if err != nil {
+		return err
+	}
+	user, err := p.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword))
+	if err != nil {
+		err = p.recordPasswordChangeFailure(ctx, userId, ip)
+		if err != nil {
+			return err
+		}
+		return error2.WrongPasswordErr
+	}
+	return p.setPassword(ctx, userId, newPassword)
+}
+
+// ForgotPassword mails a single use reset token to the owner of the email. It succeeds whether or not the email
+// belongs to a user so that the endpoint cannot be used to find out which emails are registered. For the same
+// reason the mail is sent after responding, and requests over the limit for the email or the IP address are dropped
+// without telling the caller.
+func (p *passwordService) ForgotPassword(ctx context.Context, email string) error {
+	limited, err := p.isResetLimited(ctx, email, clientIpFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if limited {
+		return nil
+	}
+	go p.sendResetMail(context.WithoutCancel(ctx), email)
+	return nil
+}
+
+func (p *passwordService) isResetLimited(ctx context.Context, email string, ip string) (bool, error) {
+	requests, err := p.loginAttemptStore.RecordFailure(ctx, redis.PasswordResetScopeIp, ip, p.resetLimit.Window)
+	if err != nil {
+		return false, err
+	}
+	if requests > p.resetLimit.MaxPerIp {
+		log.Println("Password service, too many reset requests from ip :", ip)
+		return true, nil
+	}
+	requests, err = p.loginAttemptStore.RecordFailure(ctx, redis.PasswordResetScopeEmail, strings.ToLower(email), p.resetLimit.Window)
+	if err != nil {
+		return false, err
+	}
+	if requests > p.resetLimit.MaxPerEmail {
+		log.Println("Password service, too many reset requests for an email from ip :", ip)
+		return true, nil
+	}
+	return false, nil
+}
+
+// sendResetMail runs after the response has been sent, so errors can only be logged.
+func (p *passwordService) sendResetMail(ctx context.Context, email string) {
+	user, err := p.userRepo.GetUserByEmail(ctx, email, nil)
+	if err != nil {
+		return
+	}
+	token, err := utils.GenerateToken()
+	if err != nil {
+		log.Println("Password service, generate reset token err :", err)
+		return
+	}
+	err = p.passwordResetStore.SaveResetToken(ctx, utils.HashToken(token), user.Id, p.resetDuration)
+	if err != nil {
+		return
+	}
+	body := fmt.Sprintf("Hello %s,\n\nUse the following token to reset your password. It expires in %d minutes and can be used once.\n\n%s\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+		user.Name, int(p.resetDuration.Minutes()), token)
+	err = p.mailSender.Send(ctx, user.Email, "Reset your password", body)
+	if err != nil {
+		log.Println("Password service, send reset mail err :", err)
+	}
+}
+
+func (p *passwordService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	userId, found, err := p.passwordResetStore.ConsumeResetToken(ctx, utils.HashToken(token))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return &error2.InvalidInputErr{Message: "Invalid or expired reset token"}
+	}
+	return p.setPassword(ctx, userId, newPassword)
+}
+
+func (p *passwordService) setPassword(ctx context.Context, userId string, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Println("Password service, hash password err :", err)
+		return err
+	}
+	err = p.userRepo.UpdatePassword(ctx, userId, string(hash), nil)
+	if err != nil {
+		return err
+	}
+	return revokeUserSessions(ctx, p.sessionStore, p.refreshTokenStore, userId)
+}
+
+func NewPasswordService(userRepo postgres.UserRepo, authEventRepo postgres.AuthEventRepo, passwordResetStore redis.PasswordResetStore, sessionStore redis.SessionStore, refreshTokenStore redis.RefreshTokenStore, loginAttemptStore redis.LoginAttemptStore, mailSender utils.MailSender, resetDuration time.Duration, resetLimit utils.PasswordResetLimit, accountPolicy utils.LoginThrottlePolicy, ipPolicy utils.LoginThrottlePolicy) PasswordService {
+	return &passwordService{
+		loginThrottle: loginThrottle{
+			loginAttemptStore: loginAttemptStore,
+			authEventRepo:     authEventRepo,
+			accountPolicy:     accountPolicy,
+			ipPolicy:          ipPolicy,
+		},
+		userRepo:           userRepo,
+		passwordResetStore: passwordResetStore,
+		sessionStore:       sessionStore,
+		refreshTokenStore:  refreshTokenStore,
+		mailSender:         mailSender,
+		resetDuration:      resetDuration,
+		resetLimit:         resetLimit,
+	}
+}
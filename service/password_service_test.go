@@ -0,0 +1,244 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/repo/redis"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The fakes embed the interface they stand in for, so a method the password service is not expected to call panics.
+
+type fakeUserRepo struct {
+	postgres.UserRepo
+	mu    sync.Mutex
+	users map[string]model.User
+}
+
+func (f *fakeUserRepo) GetUserByEmail(_ context.Context, email string, _ *sqlx.Tx) (model.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, user := range f.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return model.User{}, &error2.ResourceNotFoundErr{Resource: "User"}
+}
+
+func (f *fakeUserRepo) UpdatePassword(_ context.Context, id string, password string, _ *sqlx.Tx) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user := f.users[id]
+	user.Password = password
+	f.users[id] = user
+	return nil
+}
+
+type fakePasswordResetStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func (f *fakePasswordResetStore) SaveResetToken(_ context.Context, tokenHash string, userId string, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[tokenHash] = userId
+	return nil
+}
+
+func (f *fakePasswordResetStore) ConsumeResetToken(_ context.Context, tokenHash string) (string, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	userId, found := f.tokens[tokenHash]
+	delete(f.tokens, tokenHash)
+	return userId, found, nil
+}
+
+type fakeSessionStore struct {
+	redis.SessionStore
+}
+
+func (*fakeSessionStore) RevokeUserSessions(context.Context, string) error {
+	return nil
+}
+
+type fakeRefreshTokenStore struct {
+	redis.RefreshTokenStore
+}
+
+func (*fakeRefreshTokenStore) RevokeUserFamilies(context.Context, string) error {
+	return nil
+}
+
+// fakeLoginAttemptStore counts without ever expiring the counts, the tests run well within any window.
+type fakeLoginAttemptStore struct {
+	redis.LoginAttemptStore
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (f *fakeLoginAttemptStore) RecordFailure(_ context.Context, scope string, subject string, _ time.Duration) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counts[scope+"#"+subject]++
+	return f.counts[scope+"#"+subject], nil
+}
+
+type sentMail struct {
+	to   string
+	body string
+}
+
+type fakeMailSender struct {
+	mails chan sentMail
+}
+
+func (f *fakeMailSender) Send(_ context.Context, to string, _ string, body string) error {
+	f.mails <- sentMail{to: to, body: body}
+	return nil
+}
+
+// receive waits for a mail, ForgotPassword sends it after returning.
+func (f *fakeMailSender) receive(t *testing.T) sentMail {
+	t.Helper()
+	select {
+	case mail := <-f.mails:
+		return mail
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail was sent")
+		return sentMail{}
+	}
+}
+
+func (f *fakeMailSender) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case mail := <-f.mails:
+		t.Fatalf("unexpected mail to %s", mail.to)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func newTestPasswordService(users ...model.User) (*passwordService, *fakeUserRepo, *fakeMailSender) {
+	userRepo := &fakeUserRepo{users: make(map[string]model.User)}
+	for _, user := range users {
+		userRepo.users[user.Id] = user
+	}
+	mailSender := &fakeMailSender{mails: make(chan sentMail, 10)}
+	service := NewPasswordService(
+		userRepo,
+		nil,
+		&fakePasswordResetStore{tokens: make(map[string]string)},
+		&fakeSessionStore{},
+		&fakeRefreshTokenStore{},
+		&fakeLoginAttemptStore{counts: make(map[string]int)},
+		mailSender,
+		30*time.Minute,
+		utils.PasswordResetLimit{MaxPerEmail: 2, MaxPerIp: 4, Window: time.Hour},
+		utils.LoginThrottlePolicy{},
+		utils.LoginThrottlePolicy{},
+	)
+	return service.(*passwordService), userRepo, mailSender
+}
+
+// resetToken takes the token from the reset mail, where it stands alone in its paragraph.
+func resetToken(t *testing.T, body string) string {
+	t.Helper()
+	paragraphs := strings.Split(body, "\n\n")
+	if len(paragraphs) < 3 {
+		t.Fatalf("unexpected reset mail %q", body)
+	}
+	return paragraphs[2]
+}
+
+func TestPasswordServiceResetFlow(t *testing.T) {
+	service, userRepo, mailSender := newTestPasswordService(model.User{Id: "ST1", Name: "Ada", Email: "ada@example.edu"})
+	ctx := context.WithValue(context.Background(), middleware.ClientIpContextKey, "10.0.0.1")
+
+	err := service.ForgotPassword(ctx, "Ada@Example.edu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mail := mailSender.receive(t)
+	if mail.to != "ada@example.edu" {
+		t.Fatalf("reset mail sent to %s", mail.to)
+	}
+	token := resetToken(t, mail.body)
+
+	err = service.ResetPassword(ctx, token, "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(userRepo.users["ST1"].Password), []byte("new password"))
+	if err != nil {
+		t.Fatal("the password was not changed to the new one")
+	}
+
+	err = service.ResetPassword(ctx, token, "another password")
+	var invalidInputErr *error2.InvalidInputErr
+	if !errors.As(err, &invalidInputErr) {
+		t.Fatalf("expected a used token to be rejected, got %v", err)
+	}
+}
+
+func TestPasswordServiceForgotPasswordUnknownEmail(t *testing.T) {
+	service, _, mailSender := newTestPasswordService(model.User{Id: "ST1", Name: "Ada", Email: "ada@example.edu"})
+	ctx := context.WithValue(context.Background(), middleware.ClientIpContextKey, "10.0.0.1")
+
+	err := service.ForgotPassword(ctx, "nobody@example.edu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailSender.expectNone(t)
+}
+
+func TestPasswordServiceForgotPasswordLimits(t *testing.T) {
+	service, _, mailSender := newTestPasswordService(
+		model.User{Id: "ST1", Name: "Ada", Email: "ada@example.edu"},
+		model.User{Id: "ST2", Name: "Alan", Email: "alan@example.edu"},
+	)
+	ctx := context.WithValue(context.Background(), middleware.ClientIpContextKey, "10.0.0.1")
+
+	// Only two requests per email are mailed, however the email is written.
+	for _, email := range []string{"ada@example.edu", "ADA@example.edu", "ada@example.edu"} {
+		err := service.ForgotPassword(ctx, email)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mailSender.receive(t)
+	mailSender.receive(t)
+	mailSender.expectNone(t)
+
+	// The IP address has used three of its four requests, so only the first mail to the other user goes out.
+	for i := 0; i < 2; i++ {
+		err := service.ForgotPassword(ctx, "alan@example.edu")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mailSender.receive(t)
+	mailSender.expectNone(t)
+
+	// Requests cut off by the IP address do not count for the email, another address gets its second mail.
+	otherIp := context.WithValue(context.Background(), middleware.ClientIpContextKey, "10.0.0.2")
+	for i := 0; i < 2; i++ {
+		err := service.ForgotPassword(otherIp, "alan@example.edu")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mailSender.receive(t)
+	mailSender.expectNone(t)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"SchoolManagement/dto"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+type RoomService interface {
+	CreateRoom(ctx context.Context, room model.Room) error
+	UpdateRoom(ctx context.Context, room model.Room) error
+	DeleteRoomById(ctx context.Context, id string) error
+	GetRoomById(ctx context.Context, id string) (model.Room, error)
+	GetRoomList(ctx context.Context, params dto.GetRoomsParams) ([]model.Room, error)
+	FindFreeRooms(ctx context.Context, from time.Time, to time.Time, minCapacity int, features []string) ([]model.Room, error)
+}
+
+type roomService struct {
+	roomRepo           postgres.RoomRepo
+	courseRepo         postgres.CourseRepo
+	transactionManager repo.TransactionManager
+	authMiddleware     middleware.AuthMiddleware
+}
+
+func (r *roomService) CreateRoom(ctx context.Context, room model.Room) error {
+	err := r.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Require admin role to create room"}
+	}
+	if room.Features == nil {
+		room.Features = pq.StringArray{}
+	}
+	return r.roomRepo.InsertRoom(ctx, room, nil)
+}
+
+func (r *roomService) UpdateRoom(ctx context.Context, room model.Room) error {
+	err := r.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Require admin role to update room"}
+	}
+	return r.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		// Schedules are locked so that no course is scheduled in the room between the check and the update.
+		e := r.courseRepo.LockCourseSchedules(ctx, tx)
+		if e != nil {
+			return e
+		}
+		e = r.roomRepo.UpdateRoom(ctx, room, tx)
+		if e != nil || room.Capacity == 0 {
+			return e
+		}
+		course, e := r.courseRepo.GetLargestCourseByRoom(ctx, room.Id, tx)
+		var notFoundErr *error2.ResourceNotFoundErr
+		if errors.As(e, &notFoundErr) {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		if room.Capacity < course.Capacity {
+			return &error2.InvalidInputErr{Message: fmt.Sprintf("room %s would hold %d students but course %s scheduled in it needs %d", room.Id, room.Capacity, course.Id, course.Capacity)}
+		}
+		return nil
+	})
+}
+
+func (r *roomService) DeleteRoomById(ctx context.Context, id string) error {
+	err := r.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Require admin role to delete room"}
+	}
+	return r.roomRepo.DeleteRoomById(ctx, id, nil)
+}
+
+func (r *roomService) GetRoomById(ctx context.Context, id string) (model.Room, error) {
+	return r.roomRepo.GetRoomById(ctx, id, nil)
+}
+
+func (r *roomService) GetRoomList(ctx context.Context, params dto.GetRoomsParams) ([]model.Room, error) {
+	return r.roomRepo.GetRoomList(ctx, params, nil)
+}
+
+// FindFreeRooms returns the rooms matching the capacity and features that have no session within [from, to).
+func (r *roomService) FindFreeRooms(ctx context.Context, from time.Time, to time.Time, minCapacity int, features []string) ([]model.Room, error) {
+	if !from.Before(to) {
+		return nil, &error2.InvalidInputErr{Message: "from must be before to"}
+	}
+	rooms, err := r.roomRepo.GetRoomList(ctx, dto.GetRoomsParams{MinCapacity: minCapacity, Features: features}, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Schedule dates are local to each schedule, so widen the UTC window by a day on each side.
+	schedules, err := r.courseRepo.GetSchedulesInDateRange(ctx, from.AddDate(0, 0, -1).Format(utils.DateLayout), to.AddDate(0, 0, 1).Format(utils.DateLayout), nil)
+	if err != nil {
+		return nil, err
+	}
+	busyRooms := make(map[string]bool)
+	for _, schedule := range schedules {
+		if busyRooms[schedule.Room] {
+			continue
+		}
+		sessions, e := utils.ExpandCourseSchedule(schedule, from, to)
+		if e != nil {
+			return nil, e
+		}
+		if len(sessions) > 0 {
+			busyRooms[schedule.Room] = true
+		}
+	}
+	var freeRooms []model.Room
+	for _, room := range rooms {
+		if !busyRooms[room.Id] {
+			freeRooms = append(freeRooms, room)
+		}
+	}
+	if len(freeRooms) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "Free room"}
+	}
+	return freeRooms, nil
+}
+
+func NewRoomService(roomRepo postgres.RoomRepo, courseRepo postgres.CourseRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware) RoomService {
+	return &roomService{roomRepo: roomRepo, courseRepo: courseRepo, transactionManager: transactionManager, authMiddleware: authMiddleware}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/jmoiron/sqlx"
+	"math"
+)
+
+type ScoreService interface {
+	SetComponentScores(ctx context.Context, courseId string, components []model.ComponentScore) error
+	GetComponentScores(ctx context.Context, courseId string) ([]model.ComponentScore, error)
+	RecordStudentScores(ctx context.Context, courseId string, scores []model.StudentScore) error
+	GetStudentScores(ctx context.Context, courseId string) ([]model.StudentScore, error)
+	PreviewFinalGrades(ctx context.Context, courseId string) ([]model.CourseRegistration, error)
+	FinalizeGrades(ctx context.Context, courseId string) error
+	ReopenGrades(ctx context.Context, courseId string) error
+}
+
+type scoreService struct {
+	scoreRepo          postgres.ScoreRepo
+	courseRepo         postgres.CourseRepo
+	transactionManager repo.TransactionManager
+	authMiddleware     middleware.AuthMiddleware
+	gradeUtils         utils.GradeUtils
+}
+
+// checkCourseTeacher lets only the teacher of the course through. Admins do not grade, they can only reopen
+// finalized grades.
+func (s *scoreService) checkCourseTeacher(ctx context.Context, courseId string, tx *sqlx.Tx) error {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleTeacher)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required course teacher to manage scores"}
+	}
+	course, err := s.courseRepo.GetCourseById(ctx, courseId, tx)
+	if err != nil {
+		return err
+	}
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["userId"].(string) != course.TeacherId {
+		return &error2.UnauthorizedErr{Message: "Required course teacher to manage scores"}
+	}
+	return nil
+}
+
+func (s *scoreService) checkGradesNotFinalized(ctx context.Context, courseId string, tx *sqlx.Tx) error {
+	finalized, err := s.scoreRepo.IsCourseGradeFinalized(ctx, courseId, tx)
+	if err != nil {
+		return err
+	}
+	if finalized {
+		return error2.GradesFinalizedErr
+	}
+	return nil
+}
+
+// SetComponentScores replaces the components of the course with the given ones. Components keep their id, and the
+// scores recorded for them, when it is given.
+func (s *scoreService) SetComponentScores(ctx context.Context, courseId string, components []model.ComponentScore) error {
+	var totalWeight float64
+	names := make(map[string]bool)
+	for _, component := range components {
+		totalWeight += component.ScoreWeight
+		if names[component.Name] {
+			return &error2.InvalidInputErr{Message: fmt.Sprintf("Component %s is given twice", component.Name)}
+		}
+		names[component.Name] = true
+	}
+	if math.Abs(totalWeight-100) > 1e-9 {
+		return &error2.InvalidInputErr{Message: fmt.Sprintf("Score weights must add up to 100, got %g", totalWeight)}
+	}
+	return s.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		err := s.checkCourseTeacher(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		// Serializes replacements of the same course, whose name constraint is only checked at commit.
+		_, err = s.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		err = s.checkGradesNotFinalized(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		existing, err := s.scoreRepo.GetComponentScoresByCourseId(ctx, courseId, tx)
+		var notFoundErr *error2.ResourceNotFoundErr
+		if err != nil && !errors.As(err, &notFoundErr) {
+			return err
+		}
+		existingIds := make(map[int]bool)
+		for _, component := range existing {
+			existingIds[component.Id] = true
+		}
+		for _, component := range components {
+			if component.Id != 0 && !existingIds[component.Id] {
+				return &error2.InvalidInputErr{Message: fmt.Sprintf("Component %d does not belong to course %s", component.Id, courseId)}
+			}
+		}
+		return s.scoreRepo.ReplaceComponentScores(ctx, courseId, components, tx)
+	})
+}
+
+func (s *scoreService) GetComponentScores(ctx context.Context, courseId string) ([]model.ComponentScore, error) {
+	err := s.checkCourseTeacher(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreRepo.GetComponentScoresByCourseId(ctx, courseId, nil)
+}
+
+func (s *scoreService) RecordStudentScores(ctx context.Context, courseId string, scores []model.StudentScore) error {
+	return s.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		err := s.checkCourseTeacher(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		err = s.checkGradesNotFinalized(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		components, err := s.scoreRepo.GetComponentScoresByCourseId(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		componentIds := make(map[int]bool)
+		for _, component := range components {
+			componentIds[component.Id] = true
+		}
+		for _, score := range scores {
+			if !componentIds[score.ComponentId] {
+				return &error2.InvalidInputErr{Message: fmt.Sprintf("Component %d does not belong to course %s", score.ComponentId, courseId)}
+			}
+			if score.Score < 0 || score.Score > model.MaxScore {
+				return &error2.InvalidInputErr{Message: fmt.Sprintf("Score must be between 0 and %g", model.MaxScore)}
+			}
+			_, err = s.courseRepo.GetCourseRegistration(ctx, courseId, score.StudentId, tx)
+			if err != nil {
+				return err
+			}
+			err = s.scoreRepo.UpsertStudentScore(ctx, score, tx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *scoreService) GetStudentScores(ctx context.Context, courseId string) ([]model.StudentScore, error) {
+	err := s.checkCourseTeacher(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreRepo.GetStudentScoresByCourseId(ctx, courseId, nil)
+}
+
+// computeFinalGrades returns the grades along with the components they were computed from. Registrations missing
+// scores name the components.
+func (s *scoreService) computeFinalGrades(ctx context.Context, courseId string, tx *sqlx.Tx) ([]model.CourseRegistration, []model.ComponentScore, error) {
+	registrations, err := s.scoreRepo.GetCourseGrades(ctx, courseId, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var notFoundErr *error2.ResourceNotFoundErr
+	components, err := s.scoreRepo.GetComponentScoresByCourseId(ctx, courseId, tx)
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return nil, nil, err
+	}
+	scores, err := s.scoreRepo.GetStudentScoresByCourseId(ctx, courseId, tx)
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return nil, nil, err
+	}
+	studentScores := make(map[string]map[int]float64)
+	for _, score := range scores {
+		if studentScores[score.StudentId] == nil {
+			studentScores[score.StudentId] = make(map[int]float64)
+		}
+		studentScores[score.StudentId][score.ComponentId] = score.Score
+	}
+	for i := range registrations {
+		for _, component := range components {
+			if _, ok := studentScores[registrations[i].StudentId][component.Id]; !ok {
+				registrations[i].MissingComponents = append(registrations[i].MissingComponents, component.Name)
+			}
+		}
+		registrations[i].FinalScore = s.gradeUtils.ComputeFinalScore(components, studentScores[registrations[i].StudentId])
+		registrations[i].LetterGrade, registrations[i].GradePoint = s.gradeUtils.ConvertScore(registrations[i].FinalScore)
+	}
+	return registrations, components, nil
+}
+
+// gradesComplete reports whether the weights of the components add up to 100 and every registered student has a
+// score for every component, which is what finalizing requires.
+func gradesComplete(registrations []model.CourseRegistration, components []model.ComponentScore) bool {
+	var totalWeight float64
+	for _, component := range components {
+		totalWeight += component.ScoreWeight
+	}
+	if math.Abs(totalWeight-100) > 1e-9 {
+		return false
+	}
+	for _, registration := range registrations {
+		if len(registration.MissingComponents) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PreviewFinalGrades computes the grades as they would be finalized. Rows still missing scores list the components.
+func (s *scoreService) PreviewFinalGrades(ctx context.Context, courseId string) ([]model.CourseRegistration, error) {
+	err := s.checkCourseTeacher(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	finalized, err := s.scoreRepo.IsCourseGradeFinalized(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if finalized {
+		return s.scoreRepo.GetCourseGrades(ctx, courseId, nil)
+	}
+	registrations, components, err := s.computeFinalGrades(ctx, courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, &error2.ResourceNotFoundErr{Resource: "component scores"}
+	}
+	return registrations, nil
+}
+
+func (s *scoreService) FinalizeGrades(ctx context.Context, courseId string) error {
+	return s.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		err := s.checkCourseTeacher(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		course, err := s.courseRepo.GetCourseForUpdate(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		if course.Status != model.CourseStatusComplete {
+			return error2.CourseNotCompleteErr
+		}
+		err = s.checkGradesNotFinalized(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		registrations, components, err := s.computeFinalGrades(ctx, courseId, tx)
+		if err != nil {
+			return err
+		}
+		if !gradesComplete(registrations, components) {
+			return error2.GradesIncompleteErr
+		}
+		for _, registration := range registrations {
+			registration.GradeFinalized = true
+			err = s.scoreRepo.UpdateCourseGrade(ctx, registration, tx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *scoreService) ReopenGrades(ctx context.Context, courseId string) error {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to reopen grades"}
+	}
+	_, err = s.courseRepo.GetCourseById(ctx, courseId, nil)
+	if err != nil {
+		return err
+	}
+	return s.scoreRepo.ReopenCourseGrades(ctx, courseId, nil)
+}
+
+func NewScoreService(scoreRepo postgres.ScoreRepo, courseRepo postgres.CourseRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware, gradeUtils utils.GradeUtils) ScoreService {
+	return &scoreService{
+		scoreRepo:          scoreRepo,
+		courseRepo:         courseRepo,
+		transactionManager: transactionManager,
+		authMiddleware:     authMiddleware,
+		gradeUtils:         gradeUtils,
+	}
+}
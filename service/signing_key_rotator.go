@@ -0,0 +1,138 @@
+package service
+
+import (
+	"SchoolManagement/dto/response"
+	"SchoolManagement/repo"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"github.com/jmoiron/sqlx"
+	"log"
+	"time"
+)
+
+const (
+	// signingKeyCheckInterval is how often keys are reloaded from the database and rotated when due.
+	signingKeyCheckInterval = time.Minute
+	// signingKeyPublishDelay is how long a new key is only published before it starts signing, so that every server
+	// instance and every JWKS cache knows it by then.
+	signingKeyPublishDelay = 10 * time.Minute
+)
+
+// SigningKeyRotator keeps the asymmetric keys access tokens are signed with. A key signs for the rotation period,
+// then keeps verifying the tokens it signed for the grace period.
+type SigningKeyRotator interface {
+	Start(ctx context.Context)
+	GetJwks(ctx context.Context) response.JwksResponse
+}
+
+type signingKeyRotator struct {
+	signingKeyRepo     postgres.SigningKeyRepo
+	transactionManager repo.TransactionManager
+	jwtUtils           utils.JwtUtils
+	algorithm          string
+	keyEncryptionKey   []byte
+	rotationPeriod     time.Duration
+	gracePeriod        time.Duration
+}
+
+// Start loads the keys before returning, so that tokens can be signed right away, then rotates them in the
+// background until ctx is cancelled. Nothing runs with HS256.
+func (s *signingKeyRotator) Start(ctx context.Context) {
+	if s.algorithm == utils.JwtAlgorithmHS256 {
+		log.Println("Signing key rotator disabled, tokens are signed with HS256")
+		return
+	}
+	err := s.runOnce(ctx, time.Now())
+	if err != nil {
+		log.Println("Signing key rotator, run err: ", err)
+	}
+	go func() {
+		ticker := time.NewTicker(signingKeyCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			err := s.runOnce(ctx, time.Now())
+			if err != nil {
+				log.Println("Signing key rotator, run err: ", err)
+			}
+		}
+	}()
+}
+
+func (s *signingKeyRotator) runOnce(ctx context.Context, now time.Time) error {
+	err := s.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		e := s.signingKeyRepo.LockSigningKeys(ctx, tx)
+		if e != nil {
+			return e
+		}
+		e = s.signingKeyRepo.DeleteExpiredSigningKeys(ctx, now.Unix(), tx)
+		if e != nil {
+			return e
+		}
+		keys, e := s.signingKeyRepo.GetSigningKeys(ctx, now.Unix(), tx)
+		if e != nil {
+			return e
+		}
+		if len(keys) > 0 {
+			latest := keys[len(keys)-1]
+			if latest.Algorithm == s.algorithm && now.Sub(time.Unix(latest.ActivatesAt, 0)) < s.rotationPeriod {
+				return nil
+			}
+		}
+		return s.rotate(ctx, tx, now, len(keys) == 0)
+	})
+	if err != nil {
+		return err
+	}
+	keys, err := s.signingKeyRepo.GetSigningKeys(ctx, now.Unix(), nil)
+	if err != nil {
+		return err
+	}
+	return s.jwtUtils.SetSigningKeys(keys)
+}
+
+// rotate adds a key that takes over signing after the publish delay, or at once when there is no key to sign with
+// in the meantime. The keys it replaces expire a grace period after the takeover.
+func (s *signingKeyRotator) rotate(ctx context.Context, tx *sqlx.Tx, now time.Time, first bool) error {
+	key, err := utils.GenerateSigningKey(s.algorithm, s.keyEncryptionKey)
+	if err != nil {
+		log.Println("Signing key rotator, generate key err: ", err)
+		return err
+	}
+	activatesAt := now.Add(signingKeyPublishDelay)
+	if first {
+		activatesAt = now
+	}
+	key.ActivatesAt = activatesAt.Unix()
+	err = s.signingKeyRepo.ExpireSigningKeys(ctx, activatesAt.Add(s.gracePeriod).Unix(), tx)
+	if err != nil {
+		return err
+	}
+	err = s.signingKeyRepo.InsertSigningKey(ctx, key, tx)
+	if err != nil {
+		return err
+	}
+	log.Println("Signing key rotator, added key ", key.Kid, " signing from ", activatesAt.Format(time.RFC3339))
+	return nil
+}
+
+func (s *signingKeyRotator) GetJwks(_ context.Context) response.JwksResponse {
+	return s.jwtUtils.GetJwks()
+}
+
+func NewSigningKeyRotator(signingKeyRepo postgres.SigningKeyRepo, transactionManager repo.TransactionManager, jwtUtils utils.JwtUtils, algorithm string, keyEncryptionKey []byte, rotationPeriod time.Duration, gracePeriod time.Duration) SigningKeyRotator {
+	return &signingKeyRotator{
+		signingKeyRepo:     signingKeyRepo,
+		transactionManager: transactionManager,
+		jwtUtils:           jwtUtils,
+		algorithm:          algorithm,
+		keyEncryptionKey:   keyEncryptionKey,
+		rotationPeriod:     rotationPeriod,
+		gracePeriod:        gracePeriod,
+	}
+}
@@ -1,17 +1,20 @@
 package service
 
 import (
+	"SchoolManagement/dto"
 	error2 "SchoolManagement/error"
 	"SchoolManagement/middleware"
 	"SchoolManagement/model"
 	"SchoolManagement/repo"
 	"SchoolManagement/repo/postgres"
 	"SchoolManagement/repo/redis"
+	"SchoolManagement/utils"
 	"context"
 	"github.com/golang-jwt/jwt"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/crypto/bcrypt"
 	"log"
+	"math"
 	"reflect"
 )
 
@@ -20,6 +23,8 @@ type StudentService interface {
 	UpdateStudent(ctx context.Context, student model.Student) error
 	CreateStudent(ctx context.Context, student model.Student) error
 	DeleteStudentById(ctx context.Context, id string) error
+	GetTranscript(ctx context.Context, id string) (model.Transcript, error)
+	GetStudentList(ctx context.Context, params dto.GetStudentsParams) ([]model.Student, dto.PageInfo, error)
 }
 
 type studentService struct {
@@ -27,7 +32,10 @@ type studentService struct {
 	userRepo           postgres.UserRepo
 	transactionManager repo.TransactionManager
 	studentCache       redis.StudentCache
+	sessionStore       redis.SessionStore
+	refreshTokenStore  redis.RefreshTokenStore
 	authMiddleware     middleware.AuthMiddleware
+	scoreRepo          postgres.ScoreRepo
 }
 
 func (s *studentService) GetStudentById(ctx context.Context, id string) (model.Student, error) {
@@ -68,7 +76,12 @@ func (s *studentService) UpdateStudent(ctx context.Context, student model.Studen
 			return &error2.UnauthorizedErr{Message: "Unauthorized"}
 		}
 	}
-	if !reflect.ValueOf(student.Password).IsZero() {
+	passwordChanged := !reflect.ValueOf(student.Password).IsZero()
+	if passwordChanged {
+		// Students change their own password through /auth/password/change, which asks for the current one.
+		if claims["role"].(string) != model.RoleAdmin {
+			return &error2.UnauthorizedErr{Message: "Use /auth/password/change to change your password"}
+		}
 		hash, e := bcrypt.GenerateFromPassword([]byte(student.Password), bcrypt.DefaultCost)
 		if e != nil {
 			log.Println("Student service, update student err :", err)
@@ -92,6 +105,9 @@ func (s *studentService) UpdateStudent(ctx context.Context, student model.Studen
 		return err
 	}
 	s.studentCache.DeleteStudentById(ctx, student.Id)
+	if passwordChanged {
+		return revokeUserSessions(ctx, s.sessionStore, s.refreshTokenStore, student.Id)
+	}
 	return nil
 }
 
@@ -145,15 +161,132 @@ func (s *studentService) DeleteStudentById(ctx context.Context, id string) error
 		return err
 	}
 	s.studentCache.DeleteStudentById(ctx, id)
-	return nil
+	return revokeUserSessions(ctx, s.sessionStore, s.refreshTokenStore, id)
+}
+
+func (s *studentService) GetTranscript(ctx context.Context, id string) (model.Transcript, error) {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleStudent)
+	if err != nil {
+		return model.Transcript{}, &error2.UnauthorizedErr{
+			Message: "Required admin role or student to get transcript",
+		}
+	}
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["role"].(string) == model.RoleStudent {
+		if claims["userId"].(string) != id {
+			return model.Transcript{}, &error2.UnauthorizedErr{Message: "Unauthorized"}
+		}
+	}
+	_, err = s.studentRepo.GetStudentById(ctx, id, nil)
+	if err != nil {
+		return model.Transcript{}, err
+	}
+	grades, err := s.scoreRepo.GetFinalizedGradesByStudentId(ctx, id, nil)
+	if err != nil {
+		return model.Transcript{}, err
+	}
+
+	transcript := model.Transcript{StudentId: id}
+	var termPoints float64
+	// A retaken subject only counts its best attempt towards the cumulative GPA and earned credits.
+	bestGrades := make(map[string]model.CourseGrade)
+	for _, grade := range grades {
+		terms := transcript.Terms
+		if len(terms) == 0 || terms[len(terms)-1].TermCode != grade.TermCode {
+			termPoints = 0
+			transcript.Terms = append(transcript.Terms, model.TranscriptTerm{
+				TermCode:       grade.TermCode,
+				AcademicYear:   grade.AcademicYear,
+				SemesterNumber: grade.SemesterNumber,
+			})
+		}
+		term := &transcript.Terms[len(transcript.Terms)-1]
+		term.Courses = append(term.Courses, grade)
+		term.Credits += grade.NumberOfCredit
+		termPoints += grade.GradePoint * float64(grade.NumberOfCredit)
+		if term.Credits > 0 {
+			term.Gpa = roundGpa(termPoints / float64(term.Credits))
+		}
+		if best, ok := bestGrades[grade.SubjectId]; !ok || grade.GradePoint > best.GradePoint {
+			bestGrades[grade.SubjectId] = grade
+		}
+	}
+
+	var totalPoints float64
+	var totalCredits int
+	for _, grade := range bestGrades {
+		totalPoints += grade.GradePoint * float64(grade.NumberOfCredit)
+		totalCredits += grade.NumberOfCredit
+		if grade.GradePoint > 0 {
+			transcript.TotalEarnedCredits += grade.NumberOfCredit
+		}
+	}
+	if totalCredits > 0 {
+		transcript.CumulativeGpa = roundGpa(totalPoints / float64(totalCredits))
+	}
+	return transcript, nil
+}
+
+func (s *studentService) GetStudentList(ctx context.Context, params dto.GetStudentsParams) ([]model.Student, dto.PageInfo, error) {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, dto.PageInfo{}, &error2.UnauthorizedErr{
+			Message: "Required admin role to list students",
+		}
+	}
+	cursor, err := utils.DecodeCursor(params.Cursor, params.SortBy, params.SortOrder)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	limit := params.Limit
+	params.Limit++
+	students, err := s.studentRepo.GetStudentList(ctx, params, cursor, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	students, info := utils.BuildKeysetPage(students, limit, cursor, func(student model.Student) dto.Cursor {
+		return dto.Cursor{SortBy: params.SortBy, SortOrder: params.SortOrder, Value: studentSortValue(student, params.SortBy), Id: student.Id}
+	})
+	if params.IncludeTotal {
+		total, e := s.studentRepo.CountStudents(ctx, params, nil)
+		if e != nil {
+			return nil, dto.PageInfo{}, e
+		}
+		info.Total = &total
+	}
+	return students, info, nil
+}
+
+func studentSortValue(student model.Student, sortBy string) string {
+	switch sortBy {
+	case "name":
+		return student.Name
+	case "date_of_birth":
+		if student.DateOfBirth == "" {
+			return "-infinity"
+		}
+		return student.DateOfBirth
+	case "school_year":
+		return student.SchoolYear
+	case "major":
+		return student.Major
+	}
+	return student.Id
+}
+
+func roundGpa(gpa float64) float64 {
+	return math.Round(gpa*100) / 100
 }
 
-func NewStudentService(studentRepo postgres.StudentRepo, userRepo postgres.UserRepo, transactionManager repo.TransactionManager, studentCache redis.StudentCache, authMiddleware middleware.AuthMiddleware) StudentService {
+func NewStudentService(studentRepo postgres.StudentRepo, userRepo postgres.UserRepo, transactionManager repo.TransactionManager, studentCache redis.StudentCache, sessionStore redis.SessionStore, refreshTokenStore redis.RefreshTokenStore, authMiddleware middleware.AuthMiddleware, scoreRepo postgres.ScoreRepo) StudentService {
 	return &studentService{
 		studentRepo:        studentRepo,
 		userRepo:           userRepo,
 		transactionManager: transactionManager,
 		studentCache:       studentCache,
+		sessionStore:       sessionStore,
+		refreshTokenStore:  refreshTokenStore,
 		authMiddleware:     authMiddleware,
+		scoreRepo:          scoreRepo,
 	}
 }
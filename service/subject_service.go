@@ -5,8 +5,12 @@ import (
 	error2 "SchoolManagement/error"
 	"SchoolManagement/middleware"
 	"SchoolManagement/model"
+	"SchoolManagement/repo"
 	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
 	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
 )
 
 type SubjectService interface {
@@ -14,12 +18,17 @@ type SubjectService interface {
 	UpdateSubject(ctx context.Context, subject model.Subject) error
 	DeleteSubjectById(ctx context.Context, id string) error
 	GetSubjectById(ctx context.Context, id string) (model.Subject, error)
-	GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO) ([]model.Subject, error)
+	GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO) ([]model.Subject, dto.PageInfo, error)
+	AddPrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite) error
+	UpdatePrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite) error
+	DeletePrerequisite(ctx context.Context, subjectId string, prerequisiteId string) error
+	GetPrerequisites(ctx context.Context, subjectId string) ([]model.SubjectPrerequisite, error)
 }
 
 type subjectService struct {
-	subjectRepo    postgres.SubjectRepo
-	authMiddleware middleware.AuthMiddleware
+	subjectRepo        postgres.SubjectRepo
+	transactionManager repo.TransactionManager
+	authMiddleware     middleware.AuthMiddleware
 }
 
 func (s *subjectService) CreateSubject(ctx context.Context, subject model.Subject) error {
@@ -50,10 +59,128 @@ func (s *subjectService) GetSubjectById(ctx context.Context, id string) (model.S
 	return s.subjectRepo.GetSubjectById(ctx, id, nil)
 }
 
-func (s *subjectService) GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO) ([]model.Subject, error) {
-	return s.subjectRepo.GetSubjectList(ctx, params, nil)
+func (s *subjectService) GetSubjectList(ctx context.Context, params dto.GetSubjectsParamDTO) ([]model.Subject, dto.PageInfo, error) {
+	cursor, err := utils.DecodeCursor(params.Cursor, "id", "")
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	limit := params.Limit
+	params.Limit++
+	subjects, err := s.subjectRepo.GetSubjectList(ctx, params, cursor, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	subjects, info := utils.BuildKeysetPage(subjects, limit, cursor, func(subject model.Subject) dto.Cursor {
+		return dto.Cursor{SortBy: "id", Value: subject.Id, Id: subject.Id}
+	})
+	if params.IncludeTotal {
+		total, e := s.subjectRepo.CountSubjects(ctx, params, nil)
+		if e != nil {
+			return nil, dto.PageInfo{}, e
+		}
+		info.Total = &total
+	}
+	return subjects, info, nil
+}
+
+// checkPrerequisiteCycle rejects the edge when the prerequisite already depends on the subject.
+// A cycle made only of corequisites is allowed since those subjects can be taken together.
+func (s *subjectService) checkPrerequisiteCycle(ctx context.Context, prerequisite model.SubjectPrerequisite, tx *sqlx.Tx) error {
+	edges, err := s.subjectRepo.GetAllSubjectPrerequisites(ctx, tx)
+	if err != nil {
+		return err
+	}
+	graph := make(map[string][]model.SubjectPrerequisite)
+	for _, edge := range edges {
+		if edge.SubjectId == prerequisite.SubjectId && edge.PrerequisiteId == prerequisite.PrerequisiteId {
+			continue
+		}
+		graph[edge.SubjectId] = append(graph[edge.SubjectId], edge)
+	}
+
+	type state struct {
+		subjectId string
+		strict    bool
+	}
+	start := state{subjectId: prerequisite.PrerequisiteId, strict: prerequisite.Type == model.PrerequisiteTypePrerequisite}
+	visited := map[state]bool{start: true}
+	queue := []state{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.subjectId == prerequisite.SubjectId && current.strict {
+			return &error2.InvalidInputErr{Message: fmt.Sprintf("Subject %s already depends on %s, adding this prerequisite would create a cycle", prerequisite.PrerequisiteId, prerequisite.SubjectId)}
+		}
+		for _, edge := range graph[current.subjectId] {
+			next := state{subjectId: edge.PrerequisiteId, strict: current.strict || edge.Type == model.PrerequisiteTypePrerequisite}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *subjectService) AddPrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite) error {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Require admin role to add prerequisite"}
+	}
+	if prerequisite.SubjectId == prerequisite.PrerequisiteId {
+		return &error2.InvalidInputErr{Message: "Subject cannot be its own prerequisite"}
+	}
+	return s.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		_, e := s.subjectRepo.GetSubjectById(ctx, prerequisite.SubjectId, tx)
+		if e != nil {
+			return e
+		}
+		_, e = s.subjectRepo.GetSubjectById(ctx, prerequisite.PrerequisiteId, tx)
+		if e != nil {
+			return e
+		}
+		e = s.subjectRepo.LockSubjectPrerequisites(ctx, tx)
+		if e != nil {
+			return e
+		}
+		e = s.checkPrerequisiteCycle(ctx, prerequisite, tx)
+		if e != nil {
+			return e
+		}
+		return s.subjectRepo.InsertSubjectPrerequisite(ctx, prerequisite, tx)
+	})
+}
+
+func (s *subjectService) UpdatePrerequisite(ctx context.Context, prerequisite model.SubjectPrerequisite) error {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Require admin role to update prerequisite"}
+	}
+	return s.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		e := s.subjectRepo.LockSubjectPrerequisites(ctx, tx)
+		if e != nil {
+			return e
+		}
+		e = s.checkPrerequisiteCycle(ctx, prerequisite, tx)
+		if e != nil {
+			return e
+		}
+		return s.subjectRepo.UpdateSubjectPrerequisite(ctx, prerequisite, tx)
+	})
+}
+
+func (s *subjectService) DeletePrerequisite(ctx context.Context, subjectId string, prerequisiteId string) error {
+	err := s.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Require admin role to delete prerequisite"}
+	}
+	return s.subjectRepo.DeleteSubjectPrerequisite(ctx, subjectId, prerequisiteId, nil)
+}
+
+func (s *subjectService) GetPrerequisites(ctx context.Context, subjectId string) ([]model.SubjectPrerequisite, error) {
+	return s.subjectRepo.GetPrerequisitesBySubjectId(ctx, subjectId, nil)
 }
 
-func NewSubjectService(subjectRepo postgres.SubjectRepo, authMiddleware middleware.AuthMiddleware) SubjectService {
-	return &subjectService{subjectRepo: subjectRepo, authMiddleware: authMiddleware}
+func NewSubjectService(subjectRepo postgres.SubjectRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware) SubjectService {
+	return &subjectService{subjectRepo: subjectRepo, transactionManager: transactionManager, authMiddleware: authMiddleware}
 }
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"SchoolManagement/dto"
 	error2 "SchoolManagement/error"
 	"SchoolManagement/middleware"
 	"SchoolManagement/model"
 	"SchoolManagement/repo"
 	"SchoolManagement/repo/postgres"
 	"SchoolManagement/repo/redis"
+	"SchoolManagement/utils"
 	"context"
 	"github.com/golang-jwt/jwt"
 	"github.com/jmoiron/sqlx"
@@ -20,6 +22,7 @@ type TeacherService interface {
 	UpdateTeacher(ctx context.Context, teacher model.Teacher) error
 	CreateTeacher(ctx context.Context, teacher model.Teacher) error
 	DeleteTeacherById(ctx context.Context, id string) error
+	GetTeacherList(ctx context.Context, params dto.GetTeachersParams) ([]model.Teacher, dto.PageInfo, error)
 }
 
 type teacherService struct {
@@ -27,6 +30,8 @@ type teacherService struct {
 	teacherRepo        postgres.TeacherRepo
 	transactionManager repo.TransactionManager
 	teacherCache       redis.TeacherCache
+	sessionStore       redis.SessionStore
+	refreshTokenStore  redis.RefreshTokenStore
 	authMiddleware     middleware.AuthMiddleware
 }
 
@@ -82,7 +87,12 @@ func (t *teacherService) UpdateTeacher(ctx context.Context, teacher model.Teache
 			return &error2.UnauthorizedErr{Message: "Unauthorized"}
 		}
 	}
-	if !reflect.ValueOf(teacher.Password).IsZero() {
+	passwordChanged := !reflect.ValueOf(teacher.Password).IsZero()
+	if passwordChanged {
+		// Teachers change their own password through /auth/password/change, which asks for the current one.
+		if claims["role"].(string) != model.RoleAdmin {
+			return &error2.UnauthorizedErr{Message: "Use /auth/password/change to change your password"}
+		}
 		hash, err := bcrypt.GenerateFromPassword([]byte(teacher.Password), bcrypt.DefaultCost)
 		if err != nil {
 			log.Println("Teacher service, update teacher err :", err)
@@ -91,7 +101,15 @@ func (t *teacherService) UpdateTeacher(ctx context.Context, teacher model.Teache
 		teacher.Password = string(hash)
 	}
 
+	var roleChanged bool
 	err := t.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if !reflect.ValueOf(teacher.Role).IsZero() {
+			user, e := t.userRepo.GetUserById(ctx, teacher.Id, tx)
+			if e != nil {
+				return e
+			}
+			roleChanged = user.Role != teacher.Role
+		}
 		e := t.userRepo.UpdateUser(ctx, teacher.User, tx)
 		if e != nil {
 			return e
@@ -106,6 +124,11 @@ func (t *teacherService) UpdateTeacher(ctx context.Context, teacher model.Teache
 		return err
 	}
 	t.teacherCache.DeleteTeacherInfoById(ctx, teacher.Id)
+	if roleChanged || passwordChanged {
+		// Tokens carry the role, so the ones issued before the change must not be honoured any more. A password set
+		// by an admin logs the user out everywhere, as a password change does.
+		return revokeUserSessions(ctx, t.sessionStore, t.refreshTokenStore, teacher.Id)
+	}
 	return nil
 }
 
@@ -163,15 +186,62 @@ func (t *teacherService) DeleteTeacherById(ctx context.Context, id string) error
 		return err
 	}
 	t.teacherCache.DeleteTeacherInfoById(ctx, id)
-	return nil
+	return revokeUserSessions(ctx, t.sessionStore, t.refreshTokenStore, id)
+}
+
+func (t *teacherService) GetTeacherList(ctx context.Context, params dto.GetTeachersParams) ([]model.Teacher, dto.PageInfo, error) {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, dto.PageInfo{}, &error2.UnauthorizedErr{
+			Message: "Required admin role to list teachers",
+		}
+	}
+	cursor, err := utils.DecodeCursor(params.Cursor, params.SortBy, params.SortOrder)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	limit := params.Limit
+	params.Limit++
+	teachers, err := t.teacherRepo.GetTeacherList(ctx, params, cursor, nil)
+	if err != nil {
+		return nil, dto.PageInfo{}, err
+	}
+	teachers, info := utils.BuildKeysetPage(teachers, limit, cursor, func(teacher model.Teacher) dto.Cursor {
+		return dto.Cursor{SortBy: params.SortBy, SortOrder: params.SortOrder, Value: teacherSortValue(teacher, params.SortBy), Id: teacher.Id}
+	})
+	if params.IncludeTotal {
+		total, e := t.teacherRepo.CountTeachers(ctx, params, nil)
+		if e != nil {
+			return nil, dto.PageInfo{}, e
+		}
+		info.Total = &total
+	}
+	return teachers, info, nil
+}
+
+func teacherSortValue(teacher model.Teacher, sortBy string) string {
+	switch sortBy {
+	case "name":
+		return teacher.Name
+	case "date_of_birth":
+		if teacher.DateOfBirth == "" {
+			return "-infinity"
+		}
+		return teacher.DateOfBirth
+	case "department":
+		return teacher.Department
+	}
+	return teacher.Id
 }
 
-func NewTeacherService(userRepo postgres.UserRepo, teacherRepo postgres.TeacherRepo, transactionManager repo.TransactionManager, teacherCache redis.TeacherCache, authMiddleware middleware.AuthMiddleware) TeacherService {
+func NewTeacherService(userRepo postgres.UserRepo, teacherRepo postgres.TeacherRepo, transactionManager repo.TransactionManager, teacherCache redis.TeacherCache, sessionStore redis.SessionStore, refreshTokenStore redis.RefreshTokenStore, authMiddleware middleware.AuthMiddleware) TeacherService {
 	return &teacherService{
 		userRepo:           userRepo,
 		teacherRepo:        teacherRepo,
 		transactionManager: transactionManager,
 		teacherCache:       teacherCache,
+		sessionStore:       sessionStore,
+		refreshTokenStore:  refreshTokenStore,
 		authMiddleware:     authMiddleware,
 	}
 }
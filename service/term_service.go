@@ -0,0 +1,160 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type TermService interface {
+	CreateTerm(ctx context.Context, term model.Term) error
+	UpdateTerm(ctx context.Context, term model.Term) error
+	DeleteTermByCode(ctx context.Context, code string) error
+	GetTermByCode(ctx context.Context, code string) (model.Term, error)
+	GetTermList(ctx context.Context) ([]model.Term, error)
+	GetCurrentTerm(ctx context.Context) (model.Term, error)
+}
+
+type termService struct {
+	termRepo           postgres.TermRepo
+	transactionManager repo.TransactionManager
+	authMiddleware     middleware.AuthMiddleware
+}
+
+func (t *termService) CreateTerm(ctx context.Context, term model.Term) error {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to create term"}
+	}
+	err = validateTerm(term)
+	if err != nil {
+		return err
+	}
+	return t.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if term.IsCurrent != nil && *term.IsCurrent {
+			e := t.termRepo.ClearCurrentTerm(ctx, tx)
+			if e != nil {
+				return e
+			}
+		}
+		return t.termRepo.InsertTerm(ctx, term, tx)
+	})
+}
+
+func (t *termService) UpdateTerm(ctx context.Context, term model.Term) error {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to update term"}
+	}
+	return t.transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		existing, e := t.termRepo.GetTermByCode(ctx, term.Code, tx)
+		if e != nil {
+			return e
+		}
+		// Dates are validated together, so check the term as it will look after the update.
+		merged := mergeTerm(existing, term)
+		e = validateTerm(merged)
+		if e != nil {
+			return e
+		}
+		if term.IsCurrent != nil && *term.IsCurrent {
+			e = t.termRepo.ClearCurrentTerm(ctx, tx)
+			if e != nil {
+				return e
+			}
+		}
+		return t.termRepo.UpdateTerm(ctx, term, tx)
+	})
+}
+
+func (t *termService) DeleteTermByCode(ctx context.Context, code string) error {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to delete term"}
+	}
+	return t.termRepo.DeleteTermByCode(ctx, code, nil)
+}
+
+func (t *termService) GetTermByCode(ctx context.Context, code string) (model.Term, error) {
+	return t.termRepo.GetTermByCode(ctx, code, nil)
+}
+
+func (t *termService) GetTermList(ctx context.Context) ([]model.Term, error) {
+	return t.termRepo.GetTermList(ctx, nil)
+}
+
+func (t *termService) GetCurrentTerm(ctx context.Context) (model.Term, error) {
+	return t.termRepo.GetCurrentTerm(ctx, nil)
+}
+
+func mergeTerm(existing model.Term, update model.Term) model.Term {
+	if update.AcademicYear != "" {
+		existing.AcademicYear = update.AcademicYear
+	}
+	if update.SemesterNumber != 0 {
+		existing.SemesterNumber = update.SemesterNumber
+	}
+	if update.StartDate != "" {
+		existing.StartDate = update.StartDate
+	}
+	if update.EndDate != "" {
+		existing.EndDate = update.EndDate
+	}
+	if update.RegistrationOpen != "" {
+		existing.RegistrationOpen = update.RegistrationOpen
+	}
+	if update.RegistrationClose != "" {
+		existing.RegistrationClose = update.RegistrationClose
+	}
+	if update.AddDropDeadline != "" {
+		existing.AddDropDeadline = update.AddDropDeadline
+	}
+	return existing
+}
+
+func validateTerm(term model.Term) error {
+	if term.SemesterNumber < 1 || term.SemesterNumber > 3 {
+		return &error2.InvalidInputErr{Message: "semester number must be between 1 and 3"}
+	}
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"start date", term.StartDate},
+		{"end date", term.EndDate},
+		{"registration open", term.RegistrationOpen},
+		{"registration close", term.RegistrationClose},
+		{"add/drop deadline", term.AddDropDeadline},
+	}
+	dates := make(map[string]time.Time)
+	for _, field := range fields {
+		date, err := time.Parse(utils.DateLayout, field.value)
+		if err != nil {
+			return &error2.InvalidInputErr{Message: field.name + " must be in YYYY-MM-DD format"}
+		}
+		dates[field.name] = date
+	}
+	if dates["end date"].Before(dates["start date"]) {
+		return &error2.InvalidInputErr{Message: "start date must not be after end date"}
+	}
+	if dates["registration close"].Before(dates["registration open"]) {
+		return &error2.InvalidInputErr{Message: "registration open must not be after registration close"}
+	}
+	if dates["registration close"].After(dates["end date"]) {
+		return &error2.InvalidInputErr{Message: "registration must close before the term ends"}
+	}
+	if dates["add/drop deadline"].Before(dates["start date"]) || dates["add/drop deadline"].After(dates["end date"]) {
+		return &error2.InvalidInputErr{Message: "add/drop deadline must be within the term"}
+	}
+	return nil
+}
+
+func NewTermService(termRepo postgres.TermRepo, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware) TermService {
+	return &termService{termRepo: termRepo, transactionManager: transactionManager, authMiddleware: authMiddleware}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"sort"
+	"time"
+)
+
+type TimetableService interface {
+	GetWeeklyTimetable(ctx context.Context, userId string, week time.Time) (model.Timetable, error)
+}
+
+type timetableService struct {
+	courseRepo postgres.CourseRepo
+	userRepo   postgres.UserRepo
+	termRepo   postgres.TermRepo
+}
+
+// checkSelfOrAdmin lets admins act for anyone and other users only for themselves.
+func checkSelfOrAdmin(ctx context.Context, userId string) error {
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	if claims["role"].(string) != model.RoleAdmin && claims["userId"].(string) != userId {
+		return &error2.UnauthorizedErr{Message: "Unauthorized"}
+	}
+	return nil
+}
+
+// GetWeeklyTimetable merges the schedules of every course a student takes or a teacher teaches into the week containing week.
+func (t *timetableService) GetWeeklyTimetable(ctx context.Context, userId string, week time.Time) (model.Timetable, error) {
+	err := checkSelfOrAdmin(ctx, userId)
+	if err != nil {
+		return model.Timetable{}, err
+	}
+	user, err := t.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return model.Timetable{}, err
+	}
+
+	monday := utils.WeekStart(week)
+	timetable := model.Timetable{UserId: userId, WeekStart: monday.Format(utils.DateLayout)}
+	for day := 0; day < 7; day++ {
+		timetable.Days = append(timetable.Days, model.TimetableDay{
+			Date:      monday.AddDate(0, 0, day).Format(utils.DateLayout),
+			DayOfWeek: day + 1,
+			Entries:   []model.TimetableEntry{},
+		})
+	}
+
+	weekStart := monday.Format(utils.DateLayout)
+	weekEnd := monday.AddDate(0, 0, 6).Format(utils.DateLayout)
+	courses, err := getUserCourses(ctx, t.courseRepo, t.termRepo, user, func(term model.Term) bool {
+		return term.StartDate <= weekEnd && term.EndDate >= weekStart
+	})
+	if err != nil || len(courses) == 0 {
+		return timetable, err
+	}
+	courseIds := make([]string, 0, len(courses))
+	for id := range courses {
+		courseIds = append(courseIds, id)
+	}
+	schedules, err := t.courseRepo.GetSchedulesByCourseIds(ctx, courseIds, nil)
+	if err != nil {
+		return model.Timetable{}, err
+	}
+	for _, schedule := range schedules {
+		if _, ok := utils.ScheduleDateInWeek(schedule, monday); !ok {
+			continue
+		}
+		course := courses[schedule.CourseId]
+		day := &timetable.Days[schedule.DayOfWeek-1]
+		day.Entries = append(day.Entries, model.TimetableEntry{
+			CourseId:    course.Id,
+			SubjectName: course.SubjectName,
+			TeacherName: course.TeacherName,
+			Room:        schedule.Room,
+			StartTime:   schedule.StartTime,
+			EndTime:     schedule.EndTime,
+			Timezone:    schedule.Timezone,
+		})
+	}
+	for i := range timetable.Days {
+		entries := timetable.Days[i].Entries
+		sort.SliceStable(entries, func(a, b int) bool {
+			return entries[a].StartTime < entries[b].StartTime
+		})
+	}
+	return timetable, nil
+}
+
+// getUserCourses returns the courses of the user, by id, in every term accepted by inTerm.
+func getUserCourses(ctx context.Context, courseRepo postgres.CourseRepo, termRepo postgres.TermRepo, user model.User, inTerm func(term model.Term) bool) (map[string]model.Course, error) {
+	var notFoundErr *error2.ResourceNotFoundErr
+	terms, err := termRepo.GetTermList(ctx, nil)
+	if err != nil {
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	courses := make(map[string]model.Course)
+	for _, term := range terms {
+		if !inTerm(term) {
+			continue
+		}
+		termCourses, e := courseRepo.GetCoursesByUserId(ctx, user.Id, user.Role, term.Code, nil)
+		if e != nil {
+			if errors.As(e, &notFoundErr) {
+				continue
+			}
+			return nil, e
+		}
+		for _, course := range termCourses {
+			courses[course.Id] = course
+		}
+	}
+	return courses, nil
+}
+
+func NewTimetableService(courseRepo postgres.CourseRepo, userRepo postgres.UserRepo, termRepo postgres.TermRepo) TimetableService {
+	return &timetableService{
+		courseRepo: courseRepo,
+		userRepo:   userRepo,
+		termRepo:   termRepo,
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"SchoolManagement/dto/response"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/model"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+const loginChallengeDuration = 5 * time.Minute
+
+var invalidChallengeErr = &error2.UnauthorizedErr{Message: "Invalid or expired challenge token"}
+
+// twoFactorChallenge returns what the user still has to complete after the password step, or nil when the
+// password is enough.
+func (a *authService) twoFactorChallenge(ctx context.Context, user model.User) (*model.LoginChallenge, error) {
+	totp, err := a.twoFactorRepo.GetTotpByUserId(ctx, user.Id, nil)
+	var notFoundErr *error2.ResourceNotFoundErr
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
+	if totp.Enabled {
+		return &model.LoginChallenge{UserId: user.Id}, nil
+	}
+	required, err := a.twoFactorRepo.IsTwoFactorRequired(ctx, user.Role, nil)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return &model.LoginChallenge{UserId: user.Id, Setup: true}, nil
+	}
+	return nil, nil
+}
+
+func (a *authService) issueChallenge(ctx context.Context, challenge model.LoginChallenge) (response.LoginResponse, error) {
+	token, err := utils.GenerateToken()
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	err = a.loginChallengeStore.SaveChallenge(ctx, utils.HashToken(token), challenge, loginChallengeDuration)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	return response.LoginResponse{
+		TwoFactorRequired:      true,
+		TwoFactorSetupRequired: challenge.Setup,
+		ChallengeToken:         token,
+		ChallengeExpiresIn:     time.Now().Add(loginChallengeDuration).Unix(),
+	}, nil
+}
+
+// LoginTwoFactor completes a login with a TOTP or recovery code. When the role of the user requires two-factor
+// authentication that was not set up yet, the code confirms the enrolment started with
+// EnrollTwoFactorWithChallenge and the response carries the new recovery codes. Wrong codes count as failed logins.
+func (a *authService) LoginTwoFactor(ctx context.Context, challengeToken string, code string) (response.LoginResponse, error) {
+	ip := clientIpFromContext(ctx)
+	tokenHash := utils.HashToken(challengeToken)
+	challenge, found, err := a.loginChallengeStore.GetChallenge(ctx, tokenHash)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !found {
+		return response.LoginResponse{}, invalidChallengeErr
+	}
+	blocked, err := a.isLoginBlocked(ctx, challenge.UserId, ip)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if blocked {
+		a.recordAuthEvent(ctx, challenge.UserId, ip, model.AuthEventLoginBlocked)
+		return response.LoginResponse{}, error2.TooManyLoginAttemptsErr
+	}
+	totp, err := a.twoFactorRepo.GetTotpByUserId(ctx, challenge.UserId, nil)
+	if err != nil {
+		var notFoundErr *error2.ResourceNotFoundErr
+		if errors.As(err, &notFoundErr) && challenge.Setup {
+			return response.LoginResponse{}, &error2.InvalidInputErr{Message: "Two-factor authentication has to be set up first"}
+		}
+		return response.LoginResponse{}, err
+	}
+
+	var recoveryCodes []string
+	if challenge.Setup && !totp.Enabled {
+		recoveryCodes, err = confirmTotpEnrollment(ctx, a.twoFactorRepo, a.transactionManager, totp, code)
+	} else if totp.Enabled {
+		var ok bool
+		ok, err = verifySecondFactor(ctx, a.twoFactorRepo, totp, code)
+		if err == nil && !ok {
+			err = error2.WrongTwoFactorCodeErr
+		}
+	} else {
+		return response.LoginResponse{}, invalidChallengeErr
+	}
+	if err != nil {
+		if errors.Is(err, error2.WrongTwoFactorCodeErr) {
+			if e := a.recordTwoFactorFailure(ctx, challenge.UserId, ip); e != nil {
+				return response.LoginResponse{}, e
+			}
+		}
+		return response.LoginResponse{}, err
+	}
+
+	consumed, err := a.loginChallengeStore.ConsumeChallenge(ctx, tokenHash)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	if !consumed {
+		return response.LoginResponse{}, invalidChallengeErr
+	}
+	user, err := a.userRepo.GetUserById(ctx, challenge.UserId, nil)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	res, err := a.completeLogin(ctx, user, ip)
+	if err != nil {
+		return response.LoginResponse{}, err
+	}
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+// EnrollTwoFactorWithChallenge lets a user whose role requires two-factor authentication set it up in the middle
+// of the login, since they cannot get an access token before.
+func (a *authService) EnrollTwoFactorWithChallenge(ctx context.Context, challengeToken string) (response.TwoFactorEnrollmentResponse, error) {
+	challenge, found, err := a.loginChallengeStore.GetChallenge(ctx, utils.HashToken(challengeToken))
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+	if !found || !challenge.Setup {
+		return response.TwoFactorEnrollmentResponse{}, invalidChallengeErr
+	}
+	return startTotpEnrollment(ctx, a.twoFactorRepo, challenge.UserId)
+}
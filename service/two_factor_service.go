@@ -0,0 +1,232 @@
+package service
+
+import (
+	"SchoolManagement/dto/response"
+	error2 "SchoolManagement/error"
+	"SchoolManagement/middleware"
+	"SchoolManagement/model"
+	"SchoolManagement/repo"
+	"SchoolManagement/repo/postgres"
+	"SchoolManagement/repo/redis"
+	"SchoolManagement/utils"
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context) (response.TwoFactorEnrollmentResponse, error)
+	Confirm(ctx context.Context, code string) (response.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, code string) error
+	ResetUser(ctx context.Context, userId string) error
+	GetPolicies(ctx context.Context) ([]model.TwoFactorPolicy, error)
+	SavePolicy(ctx context.Context, policy model.TwoFactorPolicy) error
+}
+
+type twoFactorService struct {
+	loginThrottle
+	userRepo           postgres.UserRepo
+	twoFactorRepo      postgres.TwoFactorRepo
+	transactionManager repo.TransactionManager
+	authMiddleware     middleware.AuthMiddleware
+}
+
+// Enroll starts the enrolment of the logged in user with a new secret. Logins are not affected until the user
+// confirms it with a first code.
+func (t *twoFactorService) Enroll(ctx context.Context) (response.TwoFactorEnrollmentResponse, error) {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin, model.RoleTeacher)
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, &error2.UnauthorizedErr{Message: "Required admin or teacher role to enable two-factor authentication"}
+	}
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	return startTotpEnrollment(ctx, t.twoFactorRepo, claims["userId"].(string))
+}
+
+// Confirm enables the pending secret of the logged in user. Wrong codes count as failed logins, so that the
+// secret cannot be guessed with a stolen access token.
+func (t *twoFactorService) Confirm(ctx context.Context, code string) (response.RecoveryCodesResponse, error) {
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	userId := claims["userId"].(string)
+	ip := clientIpFromContext(ctx)
+	err := t.checkNotBlocked(ctx, userId, ip)
+	if err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+	totp, err := t.twoFactorRepo.GetTotpByUserId(ctx, userId, nil)
+	if err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+	if totp.Enabled {
+		return response.RecoveryCodesResponse{}, &error2.InvalidInputErr{Message: "Two-factor authentication is already enabled"}
+	}
+	codes, err := confirmTotpEnrollment(ctx, t.twoFactorRepo, t.transactionManager, totp, code)
+	if errors.Is(err, error2.WrongTwoFactorCodeErr) {
+		if e := t.recordTwoFactorFailure(ctx, userId, ip); e != nil {
+			return response.RecoveryCodesResponse{}, e
+		}
+	}
+	if err != nil {
+		return response.RecoveryCodesResponse{}, err
+	}
+	return response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off for the logged in user, who has to prove once more to hold the
+// second factor. It is refused while the role of the user requires it. Wrong codes count as failed logins.
+func (t *twoFactorService) Disable(ctx context.Context, code string) error {
+	claims := ctx.Value(middleware.JWTClaimsContextKey).(jwt.MapClaims)
+	userId := claims["userId"].(string)
+	ip := clientIpFromContext(ctx)
+	required, err := t.twoFactorRepo.IsTwoFactorRequired(ctx, claims["role"].(string), nil)
+	if err != nil {
+		return err
+	}
+	if required {
+		return &error2.InvalidInputErr{Message: "Two-factor authentication is required for your role"}
+	}
+	err = t.checkNotBlocked(ctx, userId, ip)
+	if err != nil {
+		return err
+	}
+	totp, err := t.twoFactorRepo.GetTotpByUserId(ctx, userId, nil)
+	if err != nil {
+		return err
+	}
+	if totp.Enabled {
+		ok, e := verifySecondFactor(ctx, t.twoFactorRepo, totp, code)
+		if e != nil {
+			return e
+		}
+		if !ok {
+			e = t.recordTwoFactorFailure(ctx, userId, ip)
+			if e != nil {
+				return e
+			}
+			return error2.WrongTwoFactorCodeErr
+		}
+	}
+	return t.twoFactorRepo.DeleteTotp(ctx, userId, nil)
+}
+
+// ResetUser removes the second factor of a user that lost both the authenticator and the recovery codes. If the
+// role of the user requires two-factor authentication, they will be asked to set it up again at the next login.
+func (t *twoFactorService) ResetUser(ctx context.Context, userId string) error {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to reset two-factor authentication"}
+	}
+	_, err = t.userRepo.GetUserById(ctx, userId, nil)
+	if err != nil {
+		return err
+	}
+	return t.twoFactorRepo.DeleteTotp(ctx, userId, nil)
+}
+
+func (t *twoFactorService) GetPolicies(ctx context.Context) ([]model.TwoFactorPolicy, error) {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, &error2.UnauthorizedErr{Message: "Required admin role to get two-factor policies"}
+	}
+	return t.twoFactorRepo.GetTwoFactorPolicies(ctx, nil)
+}
+
+func (t *twoFactorService) SavePolicy(ctx context.Context, policy model.TwoFactorPolicy) error {
+	err := t.authMiddleware.CheckUserAuthorities(ctx, model.RoleAdmin)
+	if err != nil {
+		return &error2.UnauthorizedErr{Message: "Required admin role to update two-factor policies"}
+	}
+	return t.twoFactorRepo.SaveTwoFactorPolicy(ctx, policy, nil)
+}
+
+// startTotpEnrollment replaces any pending secret of the user with a new one.
+func startTotpEnrollment(ctx context.Context, twoFactorRepo postgres.TwoFactorRepo, userId string) (response.TwoFactorEnrollmentResponse, error) {
+	totp, err := twoFactorRepo.GetTotpByUserId(ctx, userId, nil)
+	var notFoundErr *error2.ResourceNotFoundErr
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+	if totp.Enabled {
+		return response.TwoFactorEnrollmentResponse{}, &error2.InvalidInputErr{Message: "Two-factor authentication is already enabled"}
+	}
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+	err = twoFactorRepo.SaveTotp(ctx, model.UserTotp{
+		UserId:    userId,
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	}, nil)
+	if err != nil {
+		return response.TwoFactorEnrollmentResponse{}, err
+	}
+	return response.TwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningUri: utils.TotpProvisioningUri(utils.GetTotpIssuer(), userId, secret),
+	}, nil
+}
+
+// confirmTotpEnrollment enables a pending secret once the code proves the authenticator is set up, and returns
+// the recovery codes. They are only stored hashed, so this is the one time they can be shown.
+func confirmTotpEnrollment(ctx context.Context, twoFactorRepo postgres.TwoFactorRepo, transactionManager repo.TransactionManager, totp model.UserTotp, code string) ([]string, error) {
+	step, ok := utils.VerifyTotp(totp.Secret, code, time.Now())
+	if !ok {
+		return nil, error2.WrongTwoFactorCodeErr
+	}
+	codes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	codeHashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		codeHashes = append(codeHashes, utils.HashToken(utils.NormalizeRecoveryCode(c)))
+	}
+	err = transactionManager.ExecTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		used, e := twoFactorRepo.UseTotpStep(ctx, totp.UserId, step, tx)
+		if e != nil {
+			return e
+		}
+		if !used {
+			return error2.WrongTwoFactorCodeErr
+		}
+		e = twoFactorRepo.EnableTotp(ctx, totp.UserId, tx)
+		if e != nil {
+			return e
+		}
+		return twoFactorRepo.ReplaceRecoveryCodes(ctx, totp.UserId, codeHashes, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code, which cannot be replayed, or an unused recovery code,
+// which is used up.
+func verifySecondFactor(ctx context.Context, twoFactorRepo postgres.TwoFactorRepo, totp model.UserTotp, code string) (bool, error) {
+	if utils.IsTotpCode(code) {
+		step, ok := utils.VerifyTotp(totp.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return twoFactorRepo.UseTotpStep(ctx, totp.UserId, step, nil)
+	}
+	return twoFactorRepo.UseRecoveryCode(ctx, totp.UserId, utils.HashToken(utils.NormalizeRecoveryCode(code)), nil)
+}
+
+func NewTwoFactorService(userRepo postgres.UserRepo, twoFactorRepo postgres.TwoFactorRepo, authEventRepo postgres.AuthEventRepo, loginAttemptStore redis.LoginAttemptStore, transactionManager repo.TransactionManager, authMiddleware middleware.AuthMiddleware, accountPolicy utils.LoginThrottlePolicy, ipPolicy utils.LoginThrottlePolicy) TwoFactorService {
+	return &twoFactorService{
+		loginThrottle: loginThrottle{
+			loginAttemptStore: loginAttemptStore,
+			authEventRepo:     authEventRepo,
+			accountPolicy:     accountPolicy,
+			ipPolicy:          ipPolicy,
+		},
+		userRepo:           userRepo,
+		twoFactorRepo:      twoFactorRepo,
+		transactionManager: transactionManager,
+		authMiddleware:     authMiddleware,
+	}
+}
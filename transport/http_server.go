@@ -13,13 +13,16 @@ import (
 	"SchoolManagement/service"
 	"SchoolManagement/utils"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	http2 "github.com/go-kit/kit/transport/http"
 	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
 	redis2 "github.com/redis/go-redis/v9"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -32,10 +35,19 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	var uniqueConstraintErr *error2.UniqueConstraintErr
 	var unauthorizedErr *error2.UnauthorizedErr
 	var invalidInputErr *error2.InvalidInputErr
+	var missingPrerequisitesErr *error2.MissingPrerequisitesErr
+	var scheduleConflictErr *error2.ScheduleConflictErr
+	var statusTransitionErr *error2.StatusTransitionErr
 	var validationError validator.ValidationErrors
 	switch {
-	case errors.Is(err, error2.WrongPasswordErr):
+	case errors.Is(err, error2.WrongPasswordErr), errors.Is(err, error2.InvalidCredentialsErr):
 		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, error2.WrongTwoFactorCodeErr):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, error2.TooManyLoginAttemptsErr):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Is(err, error2.GradesFinalizedErr), errors.Is(err, error2.CourseNotCompleteErr), errors.Is(err, error2.GradesIncompleteErr):
+		w.WriteHeader(http.StatusConflict)
 	case errors.As(err, &notFoundErr):
 		w.WriteHeader(http.StatusNotFound)
 	case errors.As(err, &uniqueConstraintErr):
@@ -44,12 +56,22 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusUnauthorized)
 	case errors.As(err, &invalidInputErr):
 		w.WriteHeader(http.StatusBadRequest)
+	case errors.As(err, &missingPrerequisitesErr):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.As(err, &scheduleConflictErr):
+		w.WriteHeader(http.StatusConflict)
+	case errors.As(err, &statusTransitionErr):
+		w.WriteHeader(http.StatusConflict)
 	case errors.As(err, &validationError):
 		w.WriteHeader(http.StatusBadRequest)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 
+	if scheduleConflictErr != nil {
+		_ = json.NewEncoder(w).Encode(response.ScheduleConflictMessage{Error: err.Error(), ScheduleIds: scheduleConflictErr.ScheduleIds})
+		return
+	}
 	_ = json.NewEncoder(w).Encode(response.Message{Error: err.Error()})
 }
 
@@ -68,6 +90,300 @@ func encodeLoginResponse(_ context.Context, w http.ResponseWriter, response inte
 	return json.NewEncoder(w).Encode(response)
 }
 
+func decodeRefreshRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.RefreshTokenRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeRefreshResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeLogoutRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.LogoutRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeLogoutResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeRevokeUserSessionsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	userId := parts[len(parts)-1]
+	return userId, nil
+}
+
+func encodeRevokeUserSessionsResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeUnlockAccountRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	userId := parts[len(parts)-1]
+	return userId, nil
+}
+
+func encodeUnlockAccountResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeLoginTwoFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.TwoFactorLoginRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeLoginTwoFactorResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeEnrollTwoFactorWithChallengeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.TwoFactorChallengeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeEnrollTwoFactorWithChallengeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeStartOidcLoginRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+// oidcLoginCookieName is the cookie binding a single sign-on login to the browser that started it.
+const oidcLoginCookieName = "oidc_login"
+
+// encodeStartOidcLoginResponse redirects the browser to the provider. The URL is in the body as well, for clients
+// that open the login page themselves, but the callback still needs the cookie set here.
+func encodeStartOidcLoginResponse(_ context.Context, w http.ResponseWriter, res interface{}) error {
+	login := res.(response.OidcLoginResponse)
+	// Lax, as the provider redirects back with a top-level GET from another site.
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcLoginCookieName,
+		Value:    login.BrowserBinding,
+		Path:     "/auth/oidc",
+		HttpOnly: true,
+		Secure:   login.SecureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Location", login.AuthorizationUrl)
+	w.WriteHeader(http.StatusFound)
+	return json.NewEncoder(w).Encode(login)
+}
+
+func decodeCompleteOidcLoginRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	req := request.OidcCallbackRequest{
+		Code:             query.Get("code"),
+		State:            query.Get("state"),
+		Error:            query.Get("error"),
+		ErrorDescription: query.Get("error_description"),
+	}
+	if cookie, err := r.Cookie(oidcLoginCookieName); err == nil {
+		req.BrowserBinding = cookie.Value
+	}
+	return req, nil
+}
+
+func encodeCompleteOidcLoginResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	http.SetCookie(w, &http.Cookie{Name: oidcLoginCookieName, Path: "/auth/oidc", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeEnrollTwoFactorRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func encodeEnrollTwoFactorResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeConfirmTwoFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.TwoFactorCodeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeConfirmTwoFactorResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeDisableTwoFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.TwoFactorCodeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeDisableTwoFactorResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeResetTwoFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	userId := parts[len(parts)-1]
+	return userId, nil
+}
+
+func encodeResetTwoFactorResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetTwoFactorPoliciesRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func encodeGetTwoFactorPoliciesResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeSaveTwoFactorPolicyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.TwoFactorPolicyRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(r.URL.Path, "/")
+	req.Role = parts[len(parts)-1]
+	return req, nil
+}
+
+func encodeSaveTwoFactorPolicyResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetJwksRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+// encodeGetJwksResponse lets verifiers cache the keys for less than the time a new key is published before it signs.
+func encodeGetJwksResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeChangePasswordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.ChangePasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeChangePasswordResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeForgotPasswordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.ForgotPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeForgotPasswordResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeResetPasswordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.ResetPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeResetPasswordResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// decodePaginationParams reads the limit, offset, cursor and include_total query parameters shared by list endpoints.
+func decodePaginationParams(r *http.Request) (dto.PaginationParams, error) {
+	params := dto.PaginationParams{Cursor: r.URL.Query().Get("cursor")}
+	var err error
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return params, err
+		}
+	}
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		params.Offset, err = strconv.Atoi(offset)
+		if err != nil {
+			return params, err
+		}
+	}
+	if includeTotal := r.URL.Query().Get("include_total"); includeTotal != "" {
+		params.IncludeTotal, err = strconv.ParseBool(includeTotal)
+		if err != nil {
+			return params, err
+		}
+	}
+	return params, nil
+}
+
 func decodeRegisterStudentRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var req request.StudentRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
@@ -125,6 +441,40 @@ func encodeGetStudentByIdResponse(_ context.Context, w http.ResponseWriter, resp
 	return json.NewEncoder(w).Encode(response)
 }
 
+func decodeGetTranscriptRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-2]
+	return id, nil
+}
+
+func encodeGetTranscriptResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetStudentListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	pagination, err := decodePaginationParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return dto.GetStudentsParams{
+		PaginationParams: pagination,
+		Major:            r.URL.Query().Get("major"),
+		SchoolYear:       r.URL.Query().Get("school_year"),
+		Gender:           r.URL.Query().Get("gender"),
+		Name:             r.URL.Query().Get("name"),
+		SortBy:           r.URL.Query().Get("sort_by"),
+		SortOrder:        r.URL.Query().Get("sort_order"),
+	}, nil
+}
+
+func encodeGetStudentListResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
 func decodeRegisterTeacherRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var req request.TeacherRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
@@ -158,32 +508,612 @@ func encodeUpdateTeacherResponse(_ context.Context, w http.ResponseWriter, respo
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeDeleteTeacherRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeDeleteTeacherRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeDeleteTeacherResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetTeacherByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeGetTeacherByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetTeacherListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	pagination, err := decodePaginationParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return dto.GetTeachersParams{
+		PaginationParams: pagination,
+		Department:       r.URL.Query().Get("department"),
+		Gender:           r.URL.Query().Get("gender"),
+		Name:             r.URL.Query().Get("name"),
+		SortBy:           r.URL.Query().Get("sort_by"),
+		SortOrder:        r.URL.Query().Get("sort_order"),
+	}, nil
+}
+
+func encodeGetTeacherListResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateSubjectRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req dto.SubjectDto
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeCreateSubjectResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeUpdateSubjectRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	var req dto.SubjectDto
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Id = id
+	return req, nil
+}
+
+func encodeUpdateSubjectResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeDeleteSubjectByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeDeleteSubjectResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetSubjectByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeGetSubjectByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetSubjectListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	pagination, err := decodePaginationParams(r)
+	if err != nil {
+		return nil, err
+	}
+	return dto.GetSubjectsParamDTO{
+		PaginationParams: pagination,
+		Major:            r.URL.Query().Get("major"),
+	}, nil
+}
+
+func encodeGetSubjectListResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeAddPrerequisiteRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	subjectId := parts[len(parts)-2]
+	var req request.SubjectPrerequisiteRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.SubjectId = subjectId
+	return req, nil
+}
+
+func encodeAddPrerequisiteResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeUpdatePrerequisiteRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	subjectId := parts[len(parts)-3]
+	prerequisiteId := parts[len(parts)-1]
+	var req request.SubjectPrerequisiteRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.SubjectId = subjectId
+	req.PrerequisiteId = prerequisiteId
+	return req, nil
+}
+
+func encodeUpdatePrerequisiteResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeDeletePrerequisiteRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	subjectId := parts[len(parts)-3]
+	prerequisiteId := parts[len(parts)-1]
+	return request.SubjectPrerequisiteRequest{
+		SubjectId:      subjectId,
+		PrerequisiteId: prerequisiteId,
+	}, nil
+}
+
+func encodeDeletePrerequisiteResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetPrerequisitesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	subjectId := parts[len(parts)-2]
+	return subjectId, nil
+}
+
+func encodeGetPrerequisitesResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeCreateCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.CourseRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeCreateCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetCourseByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeGetCourseByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeUpdateCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	var req request.CourseRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.Id = id
+	return req, nil
+}
+
+func encodeUpdateCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeDeleteCourseByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeDeleteCourseByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeRegisterStudentToCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.CourseRegistrationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeRegisterStudentToCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeUnregisterStudentFromCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	userId := parts[len(parts)-1]
+	return request.CourseRegistrationRequest{
+		CourseId:  courseId,
+		StudentId: userId,
+	}, nil
+}
+
+func encodeUnregisterStudentFromCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeAddCourseScheduleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.CourseScheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func encodeAddCourseScheduleResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetCourseScheduleByCourseIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	params := dto.GetCourseSchedulesParams{
+		CourseId: r.URL.Query().Get("courseId"),
+		From:     r.URL.Query().Get("from"),
+		To:       r.URL.Query().Get("to"),
+	}
+	expand := r.URL.Query().Get("expand")
+	if expand != "" {
+		var err error
+		params.Expand, err = strconv.ParseBool(expand)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+func encodeGetCourseScheduleByCourseIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeDeleteCourseScheduleByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	id := parts[len(parts)-1]
+	return id, nil
+}
+
+func encodeDeleteCourseScheduleByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetCoursesByUserIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	pagination, err := decodePaginationParams(r)
+	if err != nil {
+		return nil, err
+	}
+	userId := r.URL.Query().Get("userId")
+	termCode := r.URL.Query().Get("term")
+	return dto.GetCoursesParams{
+		PaginationParams: pagination,
+		UserId:           userId,
+		TermCode:         termCode,
+	}, nil
+}
+
+func encodeGetCoursesByUserIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeSearchCoursesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	pagination, err := decodePaginationParams(r)
+	if err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	params := dto.SearchCoursesParams{
+		PaginationParams: pagination,
+		TermCode:         query.Get("term"),
+		SubjectId:        query.Get("subject_id"),
+		Major:            query.Get("major"),
+		TeacherId:        query.Get("teacher_id"),
+		Status:           query.Get("status"),
+		From:             query.Get("from"),
+		To:               query.Get("to"),
+	}
+	if hasFreeSeats := query.Get("has_free_seats"); hasFreeSeats != "" {
+		params.HasFreeSeats, err = strconv.ParseBool(hasFreeSeats)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if credits := query.Get("credits"); credits != "" {
+		params.Credits, err = strconv.Atoi(credits)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dayOfWeek := query.Get("day_of_week"); dayOfWeek != "" {
+		params.DayOfWeek, err = strconv.Atoi(dayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+func encodeSearchCoursesResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetCourseRosterRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-2]
+	return dto.GetCourseRosterParams{
+		CourseId: courseId,
+		Format:   r.URL.Query().Get("format"),
+	}, nil
+}
+
+func encodeGetCourseRosterResponse(_ context.Context, w http.ResponseWriter, res interface{}) error {
+	if file, ok := res.(response.CsvFile); ok {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+		w.WriteHeader(http.StatusOK)
+		writer := csv.NewWriter(w)
+		err := writer.Write(file.Header)
+		if err != nil {
+			return err
+		}
+		for _, row := range file.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = escapeCsvFormula(cell)
+			}
+			err = writer.Write(cells)
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
+
+// escapeCsvFormula prefixes cells that a spreadsheet would run as a formula with a quote, so that names and emails
+// entered by users are shown as text.
+func escapeCsvFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func decodeSetComponentScoresRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	var req request.ComponentScoresRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.CourseId = courseId
+	return req, nil
+}
+
+func encodeSetComponentScoresResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetComponentScoresRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	return courseId, nil
+}
+
+func encodeGetComponentScoresResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeRecordStudentScoresRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-2]
+	var req request.StudentScoresRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.CourseId = courseId
+	return req, nil
+}
+
+func encodeRecordStudentScoresResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetStudentScoresRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-2]
+	return courseId, nil
+}
+
+func encodeGetStudentScoresResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodePreviewFinalGradesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-2]
+	return courseId, nil
+}
+
+func encodePreviewFinalGradesResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeFinalizeGradesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	return courseId, nil
+}
+
+func encodeFinalizeGradesResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeReopenGradesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	return courseId, nil
+}
+
+func encodeReopenGradesResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeJoinWaitlistRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-2]
+	var req request.CourseRegistrationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.CourseId = courseId
+	return req, nil
+}
+
+func encodeJoinWaitlistResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeLeaveWaitlistRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	studentId := parts[len(parts)-1]
+	return request.CourseRegistrationRequest{
+		CourseId:  courseId,
+		StudentId: studentId,
+	}, nil
+}
+
+func encodeLeaveWaitlistResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeAcceptWaitlistOfferRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	courseId := parts[len(parts)-3]
+	var req request.CourseRegistrationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	req.CourseId = courseId
+	return req, nil
+}
+
+func encodeAcceptWaitlistOfferResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetWaitlistRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
-	id := parts[len(parts)-1]
-	return id, nil
+	courseId := parts[len(parts)-2]
+	return courseId, nil
 }
 
-func encodeDeleteTeacherResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetWaitlistResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeGetTeacherByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeGetCourseHistoryRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
-	id := parts[len(parts)-1]
-	return id, nil
+	courseId := parts[len(parts)-2]
+	return courseId, nil
 }
 
-func encodeGetTeacherByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetCourseHistoryResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeCreateSubjectRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req dto.SubjectDto
+func decodeCreateRoomRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.RoomRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		return nil, err
@@ -191,16 +1121,16 @@ func decodeCreateSubjectRequest(_ context.Context, r *http.Request) (interface{}
 	return req, nil
 }
 
-func encodeCreateSubjectResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeCreateRoomResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeUpdateSubjectRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeUpdateRoomRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
 	id := parts[len(parts)-1]
-	var req dto.SubjectDto
+	var req request.RoomRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		return nil, err
@@ -209,46 +1139,43 @@ func decodeUpdateSubjectRequest(_ context.Context, r *http.Request) (interface{}
 	return req, nil
 }
 
-func encodeUpdateSubjectResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeUpdateRoomResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeDeleteSubjectByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeDeleteRoomByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
 	id := parts[len(parts)-1]
 	return id, nil
 }
 
-func encodeDeleteSubjectResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeDeleteRoomResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeGetSubjectByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeGetRoomByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
 	id := parts[len(parts)-1]
 	return id, nil
 }
 
-func encodeGetSubjectByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetRoomByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeGetSubjectListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeGetRoomListRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	limit := r.URL.Query().Get("limit")
 	offset := r.URL.Query().Get("offset")
-	major := r.URL.Query().Get("major")
-	params := dto.GetSubjectsParamDTO{
-		PaginationParams: dto.PaginationParams{},
-		Major:            "",
-	}
-	if major != "" {
-		params.Major = major
+	minCapacity := r.URL.Query().Get("min_capacity")
+	features := r.URL.Query().Get("features")
+	params := dto.GetRoomsParams{
+		Building: r.URL.Query().Get("building"),
 	}
 	if limit != "" {
 		var err error
@@ -264,157 +1191,211 @@ func decodeGetSubjectListRequest(_ context.Context, r *http.Request) (interface{
 			return nil, err
 		}
 	}
+	if minCapacity != "" {
+		var err error
+		params.MinCapacity, err = strconv.Atoi(minCapacity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if features != "" {
+		params.Features = strings.Split(features, ",")
+	}
 	return params, nil
 }
 
-func encodeGetSubjectListResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetRoomListResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeCreateCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req request.CourseRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		return nil, err
+func decodeFindFreeRoomsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	minCapacity := r.URL.Query().Get("min_capacity")
+	features := r.URL.Query().Get("features")
+	params := dto.FindFreeRoomsParams{
+		From: r.URL.Query().Get("from"),
+		To:   r.URL.Query().Get("to"),
 	}
-	return req, nil
+	if minCapacity != "" {
+		var err error
+		params.MinCapacity, err = strconv.Atoi(minCapacity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if features != "" {
+		params.Features = strings.Split(features, ",")
+	}
+	return params, nil
 }
 
-func encodeCreateCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeFindFreeRoomsResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeGetCourseByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	parts := strings.Split(r.URL.Path, "/")
-	id := parts[len(parts)-1]
-	return id, nil
+func decodeCreateTermRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req request.TermRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
 }
 
-func encodeGetCourseByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeCreateTermResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeUpdateCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeUpdateTermRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
-	id := parts[len(parts)-1]
-	var req request.CourseRequest
+	code := parts[len(parts)-1]
+	var req request.TermRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		return nil, err
 	}
-	req.Id = id
+	req.Code = code
 	return req, nil
 }
 
-func encodeUpdateCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeUpdateTermResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeDeleteCourseByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeDeleteTermByCodeRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
-	id := parts[len(parts)-1]
-	return id, nil
+	code := parts[len(parts)-1]
+	return code, nil
 }
 
-func encodeDeleteCourseByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeDeleteTermResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeRegisterStudentToCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req request.CourseRegistrationRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		return nil, err
-	}
-	return req, nil
+func decodeGetTermByCodeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	code := parts[len(parts)-1]
+	return code, nil
 }
 
-func encodeRegisterStudentToCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetTermByCodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeUnregisterStudentFromCourseRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	parts := strings.Split(r.URL.Path, "/")
-	courseId := parts[len(parts)-3]
-	userId := parts[len(parts)-1]
-	return request.CourseRegistrationRequest{
-		CourseId:  courseId,
-		StudentId: userId,
-	}, nil
+func decodeGetTermListRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
 }
 
-func encodeUnregisterStudentFromCourseResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetTermListResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeAddCourseScheduleRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var req request.CourseScheduleRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		return nil, err
-	}
-	return req, nil
+func decodeGetCurrentTermRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
 }
 
-func encodeAddCourseScheduleResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetCurrentTermResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeGetCourseScheduleByCourseIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	courseId := r.URL.Query().Get("courseId")
-	return courseId, nil
+func decodeGetWeeklyTimetableRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return dto.GetTimetableParams{
+		UserId: r.URL.Query().Get("user_id"),
+		Week:   r.URL.Query().Get("week"),
+	}, nil
 }
 
-func encodeGetCourseScheduleByCourseIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetWeeklyTimetableResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeDeleteCourseScheduleByIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeCalendarTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwardedProto := r.Header.Get("X-Forwarded-Proto"); forwardedProto != "" {
+		scheme = forwardedProto
+	}
+	return dto.CalendarTokenParams{
+		UserId:  r.URL.Query().Get("user_id"),
+		BaseUrl: scheme + "://" + r.Host,
+	}, nil
+}
+
+func encodeCalendarTokenResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
+func decodeGetCalendarFeedRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	parts := strings.Split(r.URL.Path, "/")
-	id := parts[len(parts)-1]
-	return id, nil
+	token := parts[len(parts)-1]
+	return token, nil
 }
 
-func encodeDeleteCourseScheduleByIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeCalendarResponse(_ context.Context, w http.ResponseWriter, res interface{}) error {
+	file := res.(response.IcsFile)
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte(file.Content))
+	return err
+}
+
+func decodePreviewCourseStatusRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return dto.PreviewCourseStatusParams{
+		Date: r.URL.Query().Get("date"),
+	}, nil
+}
+
+func encodePreviewCourseStatusResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
 }
 
-func decodeGetCoursesByUserIdRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	semester := r.URL.Query().Get("semester")
-	s, err := strconv.Atoi(semester)
-	if err != nil {
-		return nil, err
+func decodeGetCourseStatusLogRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	limit := r.URL.Query().Get("limit")
+	offset := r.URL.Query().Get("offset")
+	params := dto.PaginationParams{}
+	if limit != "" {
+		var err error
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return nil, err
+		}
 	}
-	academicYear := r.URL.Query().Get("academicYear")
-	userId := r.URL.Query().Get("userId")
-	return dto.GetCoursesParams{
-		UserId:       userId,
-		Semester:     s,
-		AcademicYear: academicYear,
-	}, nil
+	if offset != "" {
+		var err error
+		params.Offset, err = strconv.Atoi(offset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
 }
 
-func encodeGetCoursesByUserIdResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeGetCourseStatusLogResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(response)
@@ -427,24 +1408,67 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 	transactionManager := repo.NewTransactionManager(db)
 	subjectRepo := postgres.NewSubjectRepo(db)
 	courseRepo := postgres.NewCourseRepo(db)
+	scoreRepo := postgres.NewScoreRepo(db)
+	roomRepo := postgres.NewRoomRepo(db)
+	waitlistRepo := postgres.NewWaitlistRepo(db)
+	termRepo := postgres.NewTermRepo(db)
+	courseStatusRepo := postgres.NewCourseStatusRepo(db)
+	calendarTokenRepo := postgres.NewCalendarTokenRepo(db)
+	authEventRepo := postgres.NewAuthEventRepo(db)
+	twoFactorRepo := postgres.NewTwoFactorRepo(db)
+	signingKeyRepo := postgres.NewSigningKeyRepo(db)
+	userIdentityRepo := postgres.NewUserIdentityRepo(db)
 
 	teacherCache := redis.NewTeacherCache(redisClient)
 	studentCache := redis.NewStudentCache(redisClient)
-
-	jwtUtils := utils.NewJwtUtils()
-	authMiddleware := middleware.NewAuthMiddleware(jwtUtils)
-
-	authService := service.NewAuthService(userRepo, jwtUtils)
-	teacherService := service.NewTeacherService(userRepo, teacherRepo, transactionManager, teacherCache, authMiddleware)
-	studentService := service.NewStudentService(studentRepo, userRepo, transactionManager, studentCache, authMiddleware)
-	subjectService := service.NewSubjectService(subjectRepo, authMiddleware)
-	courseService := service.NewCourseService(courseRepo, transactionManager, authMiddleware, userRepo)
+	refreshTokenStore := redis.NewRefreshTokenStore(redisClient)
+	sessionStore := redis.NewSessionStore(redisClient, utils.JwtTokenExpTime)
+	passwordResetStore := redis.NewPasswordResetStore(redisClient)
+	loginAttemptStore := redis.NewLoginAttemptStore(redisClient)
+	loginChallengeStore := redis.NewLoginChallengeStore(redisClient)
+	oidcStateStore := redis.NewOidcStateStore(redisClient)
+
+	jwtSigningAlgorithm := utils.GetJwtSigningAlgorithm()
+	jwtKeyEncryptionKey := utils.GetJwtKeyEncryptionKey(jwtSigningAlgorithm)
+	jwtUtils := utils.NewJwtUtils(jwtSigningAlgorithm, jwtKeyEncryptionKey)
+	gradeUtils := utils.NewGradeUtils()
+	oidcConfig := utils.GetOidcConfig()
+	oidcClient := utils.NewOidcClient(oidcConfig)
+	authMiddleware := middleware.NewAuthMiddleware(jwtUtils, sessionStore)
+	accountLoginThrottlePolicy := utils.GetAccountLoginThrottlePolicy()
+	ipLoginThrottlePolicy := utils.GetIpLoginThrottlePolicy()
+
+	authService := service.NewAuthService(userRepo, authEventRepo, twoFactorRepo, userIdentityRepo, studentRepo, transactionManager, jwtUtils, refreshTokenStore, sessionStore, loginAttemptStore, loginChallengeStore, oidcStateStore, oidcClient, authMiddleware, utils.GetRefreshTokenDuration(), accountLoginThrottlePolicy, ipLoginThrottlePolicy, oidcConfig)
+	teacherService := service.NewTeacherService(userRepo, teacherRepo, transactionManager, teacherCache, sessionStore, refreshTokenStore, authMiddleware)
+	studentService := service.NewStudentService(studentRepo, userRepo, transactionManager, studentCache, sessionStore, refreshTokenStore, authMiddleware, scoreRepo)
+	subjectService := service.NewSubjectService(subjectRepo, transactionManager, authMiddleware)
+	courseService := service.NewCourseService(courseRepo, transactionManager, authMiddleware, userRepo, subjectRepo, scoreRepo, roomRepo, waitlistRepo, termRepo, courseStatusRepo, utils.GetWaitlistOfferDuration())
+	scoreService := service.NewScoreService(scoreRepo, courseRepo, transactionManager, authMiddleware, gradeUtils)
+	roomService := service.NewRoomService(roomRepo, courseRepo, transactionManager, authMiddleware)
+	termService := service.NewTermService(termRepo, transactionManager, authMiddleware)
+	timetableService := service.NewTimetableService(courseRepo, userRepo, termRepo)
+	calendarService := service.NewCalendarService(calendarTokenRepo, courseRepo, userRepo, termRepo)
+	twoFactorService := service.NewTwoFactorService(userRepo, twoFactorRepo, authEventRepo, loginAttemptStore, transactionManager, authMiddleware, accountLoginThrottlePolicy, ipLoginThrottlePolicy)
+	passwordService := service.NewPasswordService(userRepo, authEventRepo, passwordResetStore, sessionStore, refreshTokenStore, loginAttemptStore, utils.NewMailSender(), utils.GetPasswordResetDuration(), utils.GetPasswordResetLimit(), accountLoginThrottlePolicy, ipLoginThrottlePolicy)
+	courseStatusScheduler := service.NewCourseStatusScheduler(courseService, courseRepo, courseStatusRepo, transactionManager, authMiddleware)
+	courseStatusScheduler.Start(context.Background(), utils.GetCourseStatusSchedulerInterval())
+	signingKeyRotator := service.NewSigningKeyRotator(signingKeyRepo, transactionManager, jwtUtils, jwtSigningAlgorithm, jwtKeyEncryptionKey, utils.GetJwtKeyRotationPeriod(), utils.GetJwtKeyGracePeriod())
+	signingKeyRotator.Start(context.Background())
 
 	authEndpoint := endpoint.NewAuthEndpoint(authService)
 	teacherEndpoint := endpoint.NewTeacherEndpoint(teacherService)
 	studentEndpoint := endpoint.NewStudentEndpoint(studentService)
 	subjectEndpoint := endpoint.NewSubjectEndpoint(subjectService)
 	courseEndpoint := endpoint.NewCourseEndpoint(courseService)
+	scoreEndpoint := endpoint.NewScoreEndpoint(scoreService)
+	roomEndpoint := endpoint.NewRoomEndpoint(roomService)
+	termEndpoint := endpoint.NewTermEndpoint(termService)
+	timetableEndpoint := endpoint.NewTimetableEndpoint(timetableService)
+	calendarEndpoint := endpoint.NewCalendarEndpoint(calendarService)
+	passwordEndpoint := endpoint.NewPasswordEndpoint(passwordService)
+	twoFactorEndpoint := endpoint.NewTwoFactorEndpoint(twoFactorService)
+	jwksEndpoint := endpoint.NewJwksEndpoint(signingKeyRotator)
+	courseStatusEndpoint := endpoint.NewCourseStatusEndpoint(courseStatusScheduler)
 
 	options := []http2.ServerOption{
 		http2.ServerErrorEncoder(encodeError),
@@ -456,6 +1480,114 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 		encodeLoginResponse,
 		options...)
 
+	startOidcLoginHandler := http2.NewServer(
+		authEndpoint.StartOidcLogin(),
+		decodeStartOidcLoginRequest,
+		encodeStartOidcLoginResponse,
+		options...)
+
+	completeOidcLoginHandler := http2.NewServer(
+		authEndpoint.CompleteOidcLogin(),
+		decodeCompleteOidcLoginRequest,
+		encodeCompleteOidcLoginResponse,
+		options...)
+
+	refreshHandler := http2.NewServer(
+		authEndpoint.Refresh(),
+		decodeRefreshRequest,
+		encodeRefreshResponse,
+		options...)
+
+	logoutHandler := http2.NewServer(
+		authEndpoint.Logout(),
+		decodeLogoutRequest,
+		encodeLogoutResponse,
+		options...)
+
+	revokeUserSessionsHandler := http2.NewServer(
+		authEndpoint.RevokeUserSessions(),
+		decodeRevokeUserSessionsRequest,
+		encodeRevokeUserSessionsResponse,
+		options...)
+
+	unlockAccountHandler := http2.NewServer(
+		authEndpoint.UnlockAccount(),
+		decodeUnlockAccountRequest,
+		encodeUnlockAccountResponse,
+		options...)
+
+	loginTwoFactorHandler := http2.NewServer(
+		authEndpoint.LoginTwoFactor(),
+		decodeLoginTwoFactorRequest,
+		encodeLoginTwoFactorResponse,
+		options...)
+
+	enrollTwoFactorWithChallengeHandler := http2.NewServer(
+		authEndpoint.EnrollTwoFactorWithChallenge(),
+		decodeEnrollTwoFactorWithChallengeRequest,
+		encodeEnrollTwoFactorWithChallengeResponse,
+		options...)
+
+	enrollTwoFactorHandler := http2.NewServer(
+		twoFactorEndpoint.Enroll(),
+		decodeEnrollTwoFactorRequest,
+		encodeEnrollTwoFactorResponse,
+		options...)
+
+	confirmTwoFactorHandler := http2.NewServer(
+		twoFactorEndpoint.Confirm(),
+		decodeConfirmTwoFactorRequest,
+		encodeConfirmTwoFactorResponse,
+		options...)
+
+	disableTwoFactorHandler := http2.NewServer(
+		twoFactorEndpoint.Disable(),
+		decodeDisableTwoFactorRequest,
+		encodeDisableTwoFactorResponse,
+		options...)
+
+	resetTwoFactorHandler := http2.NewServer(
+		twoFactorEndpoint.ResetUser(),
+		decodeResetTwoFactorRequest,
+		encodeResetTwoFactorResponse,
+		options...)
+
+	getTwoFactorPoliciesHandler := http2.NewServer(
+		twoFactorEndpoint.GetPolicies(),
+		decodeGetTwoFactorPoliciesRequest,
+		encodeGetTwoFactorPoliciesResponse,
+		options...)
+
+	saveTwoFactorPolicyHandler := http2.NewServer(
+		twoFactorEndpoint.SavePolicy(),
+		decodeSaveTwoFactorPolicyRequest,
+		encodeSaveTwoFactorPolicyResponse,
+		options...)
+
+	getJwksHandler := http2.NewServer(
+		jwksEndpoint.GetJwks(),
+		decodeGetJwksRequest,
+		encodeGetJwksResponse,
+		options...)
+
+	changePasswordHandler := http2.NewServer(
+		passwordEndpoint.ChangePassword(),
+		decodeChangePasswordRequest,
+		encodeChangePasswordResponse,
+		options...)
+
+	forgotPasswordHandler := http2.NewServer(
+		passwordEndpoint.ForgotPassword(),
+		decodeForgotPasswordRequest,
+		encodeForgotPasswordResponse,
+		options...)
+
+	resetPasswordHandler := http2.NewServer(
+		passwordEndpoint.ResetPassword(),
+		decodeResetPasswordRequest,
+		encodeResetPasswordResponse,
+		options...)
+
 	registerStudentHandler := http2.NewServer(
 		studentEndpoint.RegisterStudentEndpoint(),
 		decodeRegisterStudentRequest,
@@ -480,6 +1612,18 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 		encodeGetStudentByIdResponse,
 		options...)
 
+	getStudentListHandler := http2.NewServer(
+		studentEndpoint.GetStudentListEndpoint(),
+		decodeGetStudentListRequest,
+		encodeGetStudentListResponse,
+		options...)
+
+	getTranscriptHandler := http2.NewServer(
+		studentEndpoint.GetTranscriptEndpoint(),
+		decodeGetTranscriptRequest,
+		encodeGetTranscriptResponse,
+		options...)
+
 	registerTeacherHandler := http2.NewServer(
 		teacherEndpoint.RegisterTeacherEndpoint(),
 		decodeRegisterTeacherRequest,
@@ -504,6 +1648,12 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 		encodeGetTeacherByIdResponse,
 		options...)
 
+	getTeacherListHandler := http2.NewServer(
+		teacherEndpoint.GetTeacherListEndpoint(),
+		decodeGetTeacherListRequest,
+		encodeGetTeacherListResponse,
+		options...)
+
 	createSubjectHandler := http2.NewServer(
 		subjectEndpoint.CreateSubjectEndpoint(),
 		decodeCreateSubjectRequest,
@@ -534,6 +1684,30 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 		encodeGetSubjectListResponse,
 		options...)
 
+	addPrerequisiteHandler := http2.NewServer(
+		subjectEndpoint.AddPrerequisiteEndpoint(),
+		decodeAddPrerequisiteRequest,
+		encodeAddPrerequisiteResponse,
+		options...)
+
+	updatePrerequisiteHandler := http2.NewServer(
+		subjectEndpoint.UpdatePrerequisiteEndpoint(),
+		decodeUpdatePrerequisiteRequest,
+		encodeUpdatePrerequisiteResponse,
+		options...)
+
+	deletePrerequisiteHandler := http2.NewServer(
+		subjectEndpoint.DeletePrerequisiteEndpoint(),
+		decodeDeletePrerequisiteRequest,
+		encodeDeletePrerequisiteResponse,
+		options...)
+
+	getPrerequisitesHandler := http2.NewServer(
+		subjectEndpoint.GetPrerequisitesEndpoint(),
+		decodeGetPrerequisitesRequest,
+		encodeGetPrerequisitesResponse,
+		options...)
+
 	createCourseHandler := http2.NewServer(
 		courseEndpoint.CreateCourse(),
 		decodeCreateCourseRequest,
@@ -594,22 +1768,242 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 		encodeGetCoursesByUserIdResponse,
 		options...)
 
+	setComponentScoresHandler := http2.NewServer(
+		scoreEndpoint.SetComponentScores(),
+		decodeSetComponentScoresRequest,
+		encodeSetComponentScoresResponse,
+		options...)
+
+	getComponentScoresHandler := http2.NewServer(
+		scoreEndpoint.GetComponentScores(),
+		decodeGetComponentScoresRequest,
+		encodeGetComponentScoresResponse,
+		options...)
+
+	recordStudentScoresHandler := http2.NewServer(
+		scoreEndpoint.RecordStudentScores(),
+		decodeRecordStudentScoresRequest,
+		encodeRecordStudentScoresResponse,
+		options...)
+
+	getStudentScoresHandler := http2.NewServer(
+		scoreEndpoint.GetStudentScores(),
+		decodeGetStudentScoresRequest,
+		encodeGetStudentScoresResponse,
+		options...)
+
+	previewFinalGradesHandler := http2.NewServer(
+		scoreEndpoint.PreviewFinalGrades(),
+		decodePreviewFinalGradesRequest,
+		encodePreviewFinalGradesResponse,
+		options...)
+
+	finalizeGradesHandler := http2.NewServer(
+		scoreEndpoint.FinalizeGrades(),
+		decodeFinalizeGradesRequest,
+		encodeFinalizeGradesResponse,
+		options...)
+
+	reopenGradesHandler := http2.NewServer(
+		scoreEndpoint.ReopenGrades(),
+		decodeReopenGradesRequest,
+		encodeReopenGradesResponse,
+		options...)
+
+	joinWaitlistHandler := http2.NewServer(
+		courseEndpoint.JoinWaitlist(),
+		decodeJoinWaitlistRequest,
+		encodeJoinWaitlistResponse,
+		options...)
+
+	leaveWaitlistHandler := http2.NewServer(
+		courseEndpoint.LeaveWaitlist(),
+		decodeLeaveWaitlistRequest,
+		encodeLeaveWaitlistResponse,
+		options...)
+
+	acceptWaitlistOfferHandler := http2.NewServer(
+		courseEndpoint.AcceptWaitlistOffer(),
+		decodeAcceptWaitlistOfferRequest,
+		encodeAcceptWaitlistOfferResponse,
+		options...)
+
+	getWaitlistHandler := http2.NewServer(
+		courseEndpoint.GetWaitlist(),
+		decodeGetWaitlistRequest,
+		encodeGetWaitlistResponse,
+		options...)
+
+	getCourseHistoryHandler := http2.NewServer(
+		courseEndpoint.GetCourseHistory(),
+		decodeGetCourseHistoryRequest,
+		encodeGetCourseHistoryResponse,
+		options...)
+
+	searchCoursesHandler := http2.NewServer(
+		courseEndpoint.SearchCourses(),
+		decodeSearchCoursesRequest,
+		encodeSearchCoursesResponse,
+		options...)
+
+	getCourseRosterHandler := http2.NewServer(
+		courseEndpoint.GetCourseRoster(),
+		decodeGetCourseRosterRequest,
+		encodeGetCourseRosterResponse,
+		options...)
+
+	createRoomHandler := http2.NewServer(
+		roomEndpoint.CreateRoomEndpoint(),
+		decodeCreateRoomRequest,
+		encodeCreateRoomResponse,
+		options...)
+
+	updateRoomHandler := http2.NewServer(
+		roomEndpoint.UpdateRoomEndpoint(),
+		decodeUpdateRoomRequest,
+		encodeUpdateRoomResponse,
+		options...)
+
+	deleteRoomByIdHandler := http2.NewServer(
+		roomEndpoint.DeleteRoomByIdEndpoint(),
+		decodeDeleteRoomByIdRequest,
+		encodeDeleteRoomResponse,
+		options...)
+
+	getRoomByIdHandler := http2.NewServer(
+		roomEndpoint.GetRoomByIdEndpoint(),
+		decodeGetRoomByIdRequest,
+		encodeGetRoomByIdResponse,
+		options...)
+
+	getRoomListHandler := http2.NewServer(
+		roomEndpoint.GetRoomListEndpoint(),
+		decodeGetRoomListRequest,
+		encodeGetRoomListResponse,
+		options...)
+
+	findFreeRoomsHandler := http2.NewServer(
+		roomEndpoint.FindFreeRoomsEndpoint(),
+		decodeFindFreeRoomsRequest,
+		encodeFindFreeRoomsResponse,
+		options...)
+
+	createTermHandler := http2.NewServer(
+		termEndpoint.CreateTermEndpoint(),
+		decodeCreateTermRequest,
+		encodeCreateTermResponse,
+		options...)
+
+	updateTermHandler := http2.NewServer(
+		termEndpoint.UpdateTermEndpoint(),
+		decodeUpdateTermRequest,
+		encodeUpdateTermResponse,
+		options...)
+
+	deleteTermByCodeHandler := http2.NewServer(
+		termEndpoint.DeleteTermByCodeEndpoint(),
+		decodeDeleteTermByCodeRequest,
+		encodeDeleteTermResponse,
+		options...)
+
+	getTermByCodeHandler := http2.NewServer(
+		termEndpoint.GetTermByCodeEndpoint(),
+		decodeGetTermByCodeRequest,
+		encodeGetTermByCodeResponse,
+		options...)
+
+	getTermListHandler := http2.NewServer(
+		termEndpoint.GetTermListEndpoint(),
+		decodeGetTermListRequest,
+		encodeGetTermListResponse,
+		options...)
+
+	getCurrentTermHandler := http2.NewServer(
+		termEndpoint.GetCurrentTermEndpoint(),
+		decodeGetCurrentTermRequest,
+		encodeGetCurrentTermResponse,
+		options...)
+
+	getWeeklyTimetableHandler := http2.NewServer(
+		timetableEndpoint.GetWeeklyTimetableEndpoint(),
+		decodeGetWeeklyTimetableRequest,
+		encodeGetWeeklyTimetableResponse,
+		options...)
+
+	createCalendarTokenHandler := http2.NewServer(
+		calendarEndpoint.CreateCalendarTokenEndpoint(),
+		decodeCalendarTokenRequest,
+		encodeCalendarTokenResponse,
+		options...)
+
+	revokeCalendarTokenHandler := http2.NewServer(
+		calendarEndpoint.RevokeCalendarTokenEndpoint(),
+		decodeCalendarTokenRequest,
+		encodeCalendarTokenResponse,
+		options...)
+
+	exportCalendarHandler := http2.NewServer(
+		calendarEndpoint.ExportCalendarEndpoint(),
+		decodeCalendarTokenRequest,
+		encodeCalendarResponse,
+		options...)
+
+	getCalendarFeedHandler := http2.NewServer(
+		calendarEndpoint.GetCalendarFeedEndpoint(),
+		decodeGetCalendarFeedRequest,
+		encodeCalendarResponse,
+		options...)
+
+	previewCourseStatusHandler := http2.NewServer(
+		courseStatusEndpoint.PreviewTransitionsEndpoint(),
+		decodePreviewCourseStatusRequest,
+		encodePreviewCourseStatusResponse,
+		options...)
+
+	getCourseStatusLogHandler := http2.NewServer(
+		courseStatusEndpoint.GetTransitionLogEndpoint(),
+		decodeGetCourseStatusLogRequest,
+		encodeGetCourseStatusLogResponse,
+		options...)
+
 	r := gin.Default()
 
-	authRoute := r.Group("/auth")
+	r.GET("/.well-known/jwks.json", gin.WrapH(getJwksHandler))
+
+	authRoute := r.Group("/auth", middleware.ExtractClientIp())
 	authRoute.POST("/login", gin.WrapH(loginHandler))
+	authRoute.GET("/oidc/login", gin.WrapH(startOidcLoginHandler))
+	authRoute.GET("/oidc/callback", gin.WrapH(completeOidcLoginHandler))
+	authRoute.POST("/refresh", gin.WrapH(refreshHandler))
+	authRoute.POST("/logout", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(logoutHandler))
+	authRoute.POST("/revoke/:userId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(revokeUserSessionsHandler))
+	authRoute.POST("/unlock/:userId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(unlockAccountHandler))
+	authRoute.POST("/login/2fa", gin.WrapH(loginTwoFactorHandler))
+	authRoute.POST("/login/2fa/enroll", gin.WrapH(enrollTwoFactorWithChallengeHandler))
+	authRoute.POST("/2fa/enroll", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(enrollTwoFactorHandler))
+	authRoute.POST("/2fa/confirm", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(confirmTwoFactorHandler))
+	authRoute.POST("/2fa/disable", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(disableTwoFactorHandler))
+	authRoute.DELETE("/2fa/:userId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(resetTwoFactorHandler))
+	authRoute.GET("/2fa/policies", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getTwoFactorPoliciesHandler))
+	authRoute.PUT("/2fa/policies/:role", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(saveTwoFactorPolicyHandler))
+	authRoute.POST("/password/change", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(changePasswordHandler))
+	authRoute.POST("/password/forgot", gin.WrapH(forgotPasswordHandler))
+	authRoute.POST("/password/reset", gin.WrapH(resetPasswordHandler))
 
 	studentRoute := r.Group("/student")
 	studentRoute.POST("/register", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(registerStudentHandler))
 	studentRoute.PATCH("/update/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(updateStudentHandler))
 	studentRoute.DELETE("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteStudentHandler))
 	studentRoute.GET("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getStudentByIdHandler))
+	studentRoute.GET("/:id/transcript", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getTranscriptHandler))
+	studentRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getStudentListHandler))
 
 	teacherRoute := r.Group("/teacher")
 	teacherRoute.POST("/register", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(registerTeacherHandler))
 	teacherRoute.PATCH("/update/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(updateTeacherHandler))
 	teacherRoute.DELETE("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteTeacherHandler))
 	teacherRoute.GET("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getTeacherByIdHandler))
+	teacherRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getTeacherListHandler))
 
 	subjectRoute := r.Group("/subject")
 	subjectRoute.POST("/create", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(createSubjectHandler))
@@ -617,6 +2011,10 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 	subjectRoute.DELETE("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteSubjectByIdHandler))
 	subjectRoute.GET("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getSubjectByIdHandler))
 	subjectRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getSubjectListHandler))
+	subjectRoute.GET("/:id/prerequisites", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getPrerequisitesHandler))
+	subjectRoute.POST("/:id/prerequisites", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(addPrerequisiteHandler))
+	subjectRoute.PATCH("/:id/prerequisites/:prerequisiteId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(updatePrerequisiteHandler))
+	subjectRoute.DELETE("/:id/prerequisites/:prerequisiteId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deletePrerequisiteHandler))
 
 	courseRoute := r.Group("/course")
 	courseRoute.POST("/create", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(createCourseHandler))
@@ -624,10 +2022,52 @@ func NewHttpServer(db *sqlx.DB, redisClient *redis2.Client) *gin.Engine {
 	courseRoute.PATCH("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(updateCourseHandler))
 	courseRoute.DELETE("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteCourseByIdHandler))
 	courseRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getCourseByUserIdHandler))
+	courseRoute.GET("/catalog", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(searchCoursesHandler))
 	courseRoute.POST("/register", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(registerStudentToCourseHandler))
 	courseRoute.DELETE("/unregister/:courseId/student/:studentId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(unregisterStudentFromCourseHandler))
 	courseRoute.POST("/schedule", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(addCourseScheduleHandler))
 	courseRoute.GET("/schedule", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getCourseSchedulesByCourseIdHandler))
 	courseRoute.DELETE("/schedule/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteCourseScheduleByIdHandler))
+	courseRoute.PUT("/:id/scores/components", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(setComponentScoresHandler))
+	courseRoute.GET("/:id/scores/components", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getComponentScoresHandler))
+	courseRoute.POST("/:id/scores", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(recordStudentScoresHandler))
+	courseRoute.GET("/:id/scores", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getStudentScoresHandler))
+	courseRoute.GET("/:id/grades", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(previewFinalGradesHandler))
+	courseRoute.POST("/:id/grades/finalize", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(finalizeGradesHandler))
+	courseRoute.POST("/:id/grades/reopen", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(reopenGradesHandler))
+	courseRoute.GET("/status/preview", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(previewCourseStatusHandler))
+	courseRoute.GET("/status/log", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getCourseStatusLogHandler))
+	courseRoute.POST("/:id/waitlist", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(joinWaitlistHandler))
+	courseRoute.GET("/:id/waitlist", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getWaitlistHandler))
+	courseRoute.GET("/:id/history", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getCourseHistoryHandler))
+	courseRoute.GET("/:id/students", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getCourseRosterHandler))
+	courseRoute.POST("/:id/waitlist/accept", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(acceptWaitlistOfferHandler))
+	courseRoute.DELETE("/:id/waitlist/:studentId", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(leaveWaitlistHandler))
+
+	termRoute := r.Group("/term")
+	termRoute.POST("/create", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(createTermHandler))
+	termRoute.PATCH("/update/:code", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(updateTermHandler))
+	termRoute.GET("/current", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getCurrentTermHandler))
+	termRoute.DELETE("/:code", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteTermByCodeHandler))
+	termRoute.GET("/:code", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getTermByCodeHandler))
+	termRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getTermListHandler))
+
+	timetableRoute := r.Group("/timetable")
+	timetableRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getWeeklyTimetableHandler))
+
+	calendarRoute := r.Group("/calendar")
+	calendarRoute.POST("/token", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(createCalendarTokenHandler))
+	calendarRoute.DELETE("/token", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(revokeCalendarTokenHandler))
+	calendarRoute.GET("/ics", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(exportCalendarHandler))
+	// Calendar apps cannot send a JWT, the feed is authenticated by the calendar token in its URL.
+	calendarRoute.GET("/feed/:token", gin.WrapH(getCalendarFeedHandler))
+
+	roomRoute := r.Group("/room")
+	roomRoute.POST("/create", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(createRoomHandler))
+	roomRoute.PATCH("/update/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(updateRoomHandler))
+	roomRoute.GET("/free", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(findFreeRoomsHandler))
+	roomRoute.DELETE("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(deleteRoomByIdHandler))
+	roomRoute.GET("/:id", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getRoomByIdHandler))
+	roomRoute.GET("", authMiddleware.ValidateAndExtractJwt(), gin.WrapH(getRoomListHandler))
 	return r
 }
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"SchoolManagement/dto"
+	error2 "SchoolManagement/error"
+	"encoding/base64"
+	"encoding/json"
+)
+
+func EncodeCursor(cursor dto.Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor handed out by EncodeCursor. It rejects cursors issued for another sort column or
+// direction. An empty sortOrder is ascending.
+func DecodeCursor(raw string, sortBy string, sortOrder string) (*dto.Cursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var cursor dto.Cursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil || cursor.Id == "" || cursor.SortBy != sortBy || isDescending(cursor.SortOrder) != isDescending(sortOrder) {
+		return nil, &error2.InvalidInputErr{Message: "invalid cursor"}
+	}
+	return &cursor, nil
+}
+
+func isDescending(sortOrder string) bool {
+	return sortOrder == "desc"
+}
+
+// BuildKeysetPage trims rows, fetched with one row more than limit after cursor, to a page and
+// works out the cursors of its neighbours. Rows of a backward page arrive in reverse order.
+// A limit of 0 means rows is the whole list.
+func BuildKeysetPage[T any](rows []T, limit int, cursor *dto.Cursor, keyOf func(T) dto.Cursor) ([]T, dto.PageInfo) {
+	var info dto.PageInfo
+	if limit <= 0 {
+		return rows, info
+	}
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, info
+	}
+	if hasMore || backward {
+		next := keyOf(rows[len(rows)-1])
+		info.NextCursor = EncodeCursor(next)
+	}
+	if (backward && hasMore) || (cursor != nil && !backward) {
+		prev := keyOf(rows[0])
+		prev.Backward = true
+		info.PrevCursor = EncodeCursor(prev)
+	}
+	return rows, info
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"SchoolManagement/model"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type GradeUtils interface {
+	ComputeFinalScore(components []model.ComponentScore, scores map[int]float64) float64
+	ConvertScore(score float64) (string, float64)
+}
+
+type gradeUtils struct {
+	scale model.GradeScale
+}
+
+func (g *gradeUtils) ComputeFinalScore(components []model.ComponentScore, scores map[int]float64) float64 {
+	var finalScore float64
+	for _, component := range components {
+		finalScore += scores[component.Id] * component.ScoreWeight / 100
+	}
+	return math.Round(finalScore*10) / 10
+}
+
+func (g *gradeUtils) ConvertScore(score float64) (string, float64) {
+	for _, level := range g.scale {
+		if score >= level.MinScore {
+			return level.Letter, level.GradePoint
+		}
+	}
+	lowest := g.scale[len(g.scale)-1]
+	return lowest.Letter, lowest.GradePoint
+}
+
+// parseGradeScale reads a scale in the form "A:8.5:4.0,B+:8.0:3.5,...,F:0:0".
+func parseGradeScale(value string) (model.GradeScale, error) {
+	var scale model.GradeScale
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 {
+			return nil, strconv.ErrSyntax
+		}
+		minScore, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		gradePoint, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return nil, err
+		}
+		scale = append(scale, model.GradeLevel{Letter: parts[0], MinScore: minScore, GradePoint: gradePoint})
+	}
+	sort.Slice(scale, func(i, j int) bool {
+		return scale[i].MinScore > scale[j].MinScore
+	})
+	return scale, nil
+}
+
+func NewGradeUtils() GradeUtils {
+	scale := model.DefaultGradeScale
+	if value := os.Getenv("GRADE_SCALE"); value != "" {
+		var err error
+		scale, err = parseGradeScale(value)
+		if err != nil {
+			log.Fatal("Invalid GRADE_SCALE: ", err)
+		}
+	}
+	return &gradeUtils{scale: scale}
+}
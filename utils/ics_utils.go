@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"SchoolManagement/model"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const icsDateTimeLayout = "20060102T150405"
+
+var icsDays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// BuildICalendar renders the schedules as an RFC 5545 calendar with one weekly recurring event per schedule.
+// Times keep the schedule timezone through TZID so that sessions stay put across daylight saving changes, and each
+// TZID is defined by a VTIMEZONE covering the dates of its schedules.
+func BuildICalendar(schedules []model.CourseSchedule, courses map[string]model.Course, now time.Time) (string, error) {
+	var timezones []string
+	ranges := make(map[string][2]time.Time)
+	for _, schedule := range schedules {
+		from, to, err := ScheduleDateRange(schedule)
+		if err != nil {
+			return "", err
+		}
+		dateRange, ok := ranges[schedule.Timezone]
+		if !ok {
+			timezones = append(timezones, schedule.Timezone)
+			dateRange = [2]time.Time{from, to}
+		}
+		if from.Before(dateRange[0]) {
+			dateRange[0] = from
+		}
+		if to.After(dateRange[1]) {
+			dateRange[1] = to
+		}
+		ranges[schedule.Timezone] = dateRange
+	}
+
+	var builder strings.Builder
+	writeIcsLine(&builder, "BEGIN:VCALENDAR")
+	writeIcsLine(&builder, "VERSION:2.0")
+	writeIcsLine(&builder, "PRODID:-//SchoolManagement//Course schedules//EN")
+	writeIcsLine(&builder, "CALSCALE:GREGORIAN")
+	writeIcsLine(&builder, "METHOD:PUBLISH")
+	writeIcsLine(&builder, "X-WR-CALNAME:Course schedules")
+	writeIcsLine(&builder, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeIcsLine(&builder, "X-PUBLISHED-TTL:PT1H")
+	for _, timezone := range timezones {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return "", err
+		}
+		writeIcsTimezone(&builder, timezone, location, ranges[timezone][0], ranges[timezone][1])
+	}
+	stamp := now.UTC().Format(icsDateTimeLayout) + "Z"
+	for _, schedule := range schedules {
+		location, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			return "", err
+		}
+		startDate, err := time.ParseInLocation(DateLayout, schedule.StartDate, location)
+		if err != nil {
+			return "", err
+		}
+		endDate, err := time.ParseInLocation(DateLayout, schedule.EndDate, location)
+		if err != nil {
+			return "", err
+		}
+		startTime, err := time.Parse(TimeLayout, schedule.StartTime)
+		if err != nil {
+			return "", err
+		}
+		endTime, err := time.Parse(TimeLayout, schedule.EndTime)
+		if err != nil {
+			return "", err
+		}
+		weekday := time.Weekday(schedule.DayOfWeek % 7)
+		first := startDate.AddDate(0, 0, (int(weekday)-int(startDate.Weekday())+7)%7)
+		if first.After(endDate) {
+			continue
+		}
+		at := func(day time.Time, clock time.Time) time.Time {
+			return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+		}
+		course := courses[schedule.CourseId]
+		writeIcsLine(&builder, "BEGIN:VEVENT")
+		writeIcsLine(&builder, "UID:course-schedule-"+strconv.Itoa(schedule.Id)+"@schoolmanagement")
+		writeIcsLine(&builder, "DTSTAMP:"+stamp)
+		writeIcsLine(&builder, "SUMMARY:"+icsEscaper.Replace(course.SubjectName))
+		writeIcsLine(&builder, "LOCATION:"+icsEscaper.Replace(schedule.Room))
+		writeIcsLine(&builder, "DESCRIPTION:"+icsEscaper.Replace("Course: "+course.Id+"\nSubject: "+course.SubjectName+"\nTeacher: "+course.TeacherName))
+		writeIcsLine(&builder, "DTSTART;TZID="+schedule.Timezone+":"+at(first, startTime).Format(icsDateTimeLayout))
+		writeIcsLine(&builder, "DTEND;TZID="+schedule.Timezone+":"+at(first, endTime).Format(icsDateTimeLayout))
+		writeIcsLine(&builder, "RRULE:FREQ=WEEKLY;BYDAY="+icsDays[weekday]+";UNTIL="+at(endDate, startTime).UTC().Format(icsDateTimeLayout)+"Z")
+		if len(schedule.ExceptionDates) > 0 {
+			var exceptions []string
+			for _, exceptionDate := range schedule.ExceptionDates {
+				day, e := time.ParseInLocation(DateLayout, exceptionDate, location)
+				if e != nil {
+					return "", e
+				}
+				exceptions = append(exceptions, at(day, startTime).Format(icsDateTimeLayout))
+			}
+			writeIcsLine(&builder, "EXDATE;TZID="+schedule.Timezone+":"+strings.Join(exceptions, ","))
+		}
+		writeIcsLine(&builder, "END:VEVENT")
+	}
+	writeIcsLine(&builder, "END:VCALENDAR")
+	return builder.String(), nil
+}
+
+// writeIcsTimezone writes a VTIMEZONE with one observance for the offset in effect at from and one for each change
+// of offset up to to. Changes are looked for day by day, then narrowed down to the second.
+func writeIcsTimezone(builder *strings.Builder, timezone string, location *time.Location, from time.Time, to time.Time) {
+	writeIcsLine(builder, "BEGIN:VTIMEZONE")
+	writeIcsLine(builder, "TZID:"+timezone)
+	current := from.In(location)
+	_, offset := current.Zone()
+	writeIcsObservance(builder, current, offset)
+	for current.Before(to) {
+		next := current.Add(24 * time.Hour)
+		if _, nextOffset := next.Zone(); nextOffset != offset {
+			before, after := current, next
+			for after.Sub(before) > time.Second {
+				middle := before.Add(after.Sub(before) / 2)
+				if _, middleOffset := middle.Zone(); middleOffset == offset {
+					before = middle
+				} else {
+					after = middle
+				}
+			}
+			writeIcsObservance(builder, after.Truncate(time.Second), offset)
+			_, offset = after.Zone()
+		}
+		current = next
+	}
+	writeIcsLine(builder, "END:VTIMEZONE")
+}
+
+// writeIcsObservance writes the observance starting at onset, whose DTSTART is the local time under the previous
+// offset.
+func writeIcsObservance(builder *strings.Builder, onset time.Time, previousOffset int) {
+	name, offset := onset.Zone()
+	kind := "STANDARD"
+	if onset.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	writeIcsLine(builder, "BEGIN:"+kind)
+	writeIcsLine(builder, "DTSTART:"+onset.In(time.FixedZone("", previousOffset)).Format(icsDateTimeLayout))
+	writeIcsLine(builder, "TZOFFSETFROM:"+formatIcsOffset(previousOffset))
+	writeIcsLine(builder, "TZOFFSETTO:"+formatIcsOffset(offset))
+	writeIcsLine(builder, "TZNAME:"+icsEscaper.Replace(name))
+	writeIcsLine(builder, "END:"+kind)
+}
+
+// formatIcsOffset renders a UTC offset in seconds as +HHMM, or +HHMMSS when it is not a whole minute.
+func formatIcsOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	formatted := sign + fmt.Sprintf("%02d%02d", offset/3600, offset/60%60)
+	if offset%60 != 0 {
+		formatted += fmt.Sprintf("%02d", offset%60)
+	}
+	return formatted
+}
+
+// writeIcsLine ends the line with CRLF and folds it so that no line is longer than 75 octets.
+func writeIcsLine(builder *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = 74
+	}
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+}
@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	JwtAlgorithmHS256 = "HS256"
+	JwtAlgorithmRS256 = "RS256"
+	JwtAlgorithmEdDSA = "EdDSA"
+)
+
+const (
+	rsaKeyBits                 = 2048
+	jwtKeyIdLength             = 16
+	privateKeyPemType          = "PRIVATE KEY"
+	keyEncryptionKeyBytes      = 32
+	encryptedPrivateKeyPrefix  = "aes-256-gcm:"
+	defaultJwtKeyRotationHours = 30 * 24
+	defaultJwtKeyGraceHours    = 24
+)
+
+// GetJwtSigningAlgorithm reads JWT_SIGNING_ALG. HS256, the default, signs with SECRET. RS256 and EdDSA sign with
+// rotating key pairs whose public halves are published as a JWKS.
+func GetJwtSigningAlgorithm() string {
+	value := os.Getenv("JWT_SIGNING_ALG")
+	switch value {
+	case "":
+		return JwtAlgorithmHS256
+	case JwtAlgorithmHS256, JwtAlgorithmRS256, JwtAlgorithmEdDSA:
+		return value
+	}
+	log.Fatal("Invalid JWT_SIGNING_ALG: ", value)
+	return ""
+}
+
+// GetJwtKeyRotationPeriod reads JWT_KEY_ROTATION_HOURS, how long a key signs before a new one replaces it.
+func GetJwtKeyRotationPeriod() time.Duration {
+	return time.Duration(getPositiveIntEnv("JWT_KEY_ROTATION_HOURS", defaultJwtKeyRotationHours)) * time.Hour
+}
+
+// GetJwtKeyGracePeriod reads JWT_KEY_GRACE_HOURS, how long a replaced key still verifies tokens. It cannot be
+// shorter than an access token lives.
+func GetJwtKeyGracePeriod() time.Duration {
+	value := os.Getenv("JWT_KEY_GRACE_HOURS")
+	if value == "" {
+		return defaultJwtKeyGraceHours * time.Hour
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil || time.Duration(hours)*time.Hour < JwtTokenExpTime {
+		log.Fatal("Invalid JWT_KEY_GRACE_HOURS: ", value)
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// GetJwtKeyEncryptionKey reads JWT_KEY_ENCRYPTION_KEY, the base64 encoded 32 byte key the private signing keys are
+// encrypted with in the database. It is required unless the algorithm is HS256.
+func GetJwtKeyEncryptionKey(algorithm string) []byte {
+	if algorithm == JwtAlgorithmHS256 {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(os.Getenv("JWT_KEY_ENCRYPTION_KEY"))
+	if err != nil || len(key) != keyEncryptionKeyBytes {
+		log.Fatal("JWT_KEY_ENCRYPTION_KEY must be 32 base64 encoded bytes when JWT_SIGNING_ALG is ", algorithm)
+	}
+	return key
+}
+
+// GenerateSigningKey creates a key pair for the algorithm, with the private key encrypted by keyEncryptionKey. The
+// caller sets the activation time.
+func GenerateSigningKey(algorithm string, keyEncryptionKey []byte) (model.SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+	switch algorithm {
+	case JwtAlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case JwtAlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return model.SigningKey{}, errors.New("unsupported signing algorithm " + algorithm)
+	}
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	kid, err := GenerateToken()
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	kid = kid[:jwtKeyIdLength]
+	encrypted, err := encryptPrivateKey(pem.EncodeToMemory(&pem.Block{Type: privateKeyPemType, Bytes: der}), kid, keyEncryptionKey)
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	return model.SigningKey{
+		Kid:        kid,
+		Algorithm:  algorithm,
+		PrivateKey: encrypted,
+	}, nil
+}
+
+// encryptPrivateKey seals the PEM with AES-256-GCM. The kid is authenticated along, so that a private key cannot be
+// moved to another row.
+func encryptPrivateKey(privateKeyPem []byte, kid string, keyEncryptionKey []byte) (string, error) {
+	aead, err := newKeyEncryptionAead(keyEncryptionKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, privateKeyPem, []byte(kid))
+	return encryptedPrivateKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptPrivateKey(key model.SigningKey, keyEncryptionKey []byte) ([]byte, error) {
+	if !strings.HasPrefix(key.PrivateKey, encryptedPrivateKeyPrefix) {
+		return nil, errors.New("signing key " + key.Kid + " is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key.PrivateKey, encryptedPrivateKeyPrefix))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newKeyEncryptionAead(keyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("signing key " + key.Kid + " is too short")
+	}
+	privateKeyPem, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(key.Kid))
+	if err != nil {
+		return nil, errors.New("signing key " + key.Kid + " cannot be decrypted with JWT_KEY_ENCRYPTION_KEY")
+	}
+	return privateKeyPem, nil
+}
+
+func newKeyEncryptionAead(keyEncryptionKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(keyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func parsePrivateKey(key model.SigningKey, keyEncryptionKey []byte) (crypto.Signer, error) {
+	privateKeyPem, err := decryptPrivateKey(key, keyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(privateKeyPem)
+	if block == nil || block.Type != privateKeyPemType {
+		return nil, errors.New("signing key " + key.Kid + " is not a PEM encoded private key")
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if key.Algorithm == JwtAlgorithmRS256 {
+			return k, nil
+		}
+	case ed25519.PrivateKey:
+		if key.Algorithm == JwtAlgorithmEdDSA {
+			return k, nil
+		}
+	}
+	return nil, errors.New("signing key " + key.Kid + " does not match algorithm " + key.Algorithm)
+}
+
+func toJwk(kid string, algorithm string, publicKey crypto.PublicKey) response.Jwk {
+	jwk := response.Jwk{Kid: kid, Use: "sig", Alg: algorithm}
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	}
+	return jwk
+}
@@ -1,30 +1,70 @@
 package utils
 
 import (
+	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
+	"crypto"
 	"errors"
 	"github.com/golang-jwt/jwt"
 	"log"
 	"os"
+	"sync"
 	"time"
 )
 
 type JwtUtils interface {
 	CreateToken(userId string, role string) (string, int64, error)
 	VerifyToken(tokenString string) (jwt.MapClaims, error)
+	SetSigningKeys(keys []model.SigningKey) error
+	GetJwks() response.JwksResponse
 }
 
-const jwtTokenExpTime = 60 * time.Minute
+const JwtTokenExpTime = 60 * time.Minute
 
-type jwtUtils struct{}
+type jwtKey struct {
+	kid         string
+	method      jwt.SigningMethod
+	privateKey  crypto.Signer
+	activatesAt int64
+}
+
+type jwtUtils struct {
+	algorithm        string
+	keyEncryptionKey []byte
+	mu               sync.RWMutex
+	// keys are ordered by activation, oldest first.
+	keys []jwtKey
+}
 
-func (*jwtUtils) CreateToken(userId string, role string) (string, int64, error) {
-	expireTime := time.Now().Add(jwtTokenExpTime).Unix()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+func (j *jwtUtils) CreateToken(userId string, role string) (string, int64, error) {
+	jti, err := GenerateToken()
+	if err != nil {
+		log.Println("Jwt service, create access token err :", err)
+		return "", 0, errors.New("internal server error")
+	}
+	now := time.Now()
+	expireTime := now.Add(JwtTokenExpTime).Unix()
+	claims := jwt.MapClaims{
+		"jti":    jti,
 		"userId": userId,
+		"iat":    float64(now.UnixMilli()) / 1000,
 		"exp":    expireTime,
 		"role":   role,
-	})
-	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET")))
+	}
+
+	var tokenString string
+	if j.algorithm == JwtAlgorithmHS256 {
+		tokenString, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("SECRET")))
+	} else {
+		key, ok := j.activeKey(now.Unix())
+		if !ok {
+			log.Println("Jwt service, create access token err : no active signing key")
+			return "", 0, errors.New("internal server error")
+		}
+		token := jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.kid
+		tokenString, err = token.SignedString(key.privateKey)
+	}
 	if err != nil {
 		log.Println("Jwt service, create access token err :", err)
 		return "", 0, errors.New("internal server error")
@@ -32,12 +72,33 @@ func (*jwtUtils) CreateToken(userId string, role string) (string, int64, error)
 	return tokenString, expireTime, nil
 }
 
-func (*jwtUtils) VerifyToken(tokenString string) (jwt.MapClaims, error) {
+// activeKey returns the most recently activated key.
+func (j *jwtUtils) activeKey(now int64) (jwtKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	for i := len(j.keys) - 1; i >= 0; i-- {
+		if j.keys[i].activatesAt <= now {
+			return j.keys[i], true
+		}
+	}
+	return jwtKey{}, false
+}
+
+func (j *jwtUtils) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if j.algorithm == JwtAlgorithmHS256 {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("token is invalid")
+			}
+			return []byte(os.Getenv("SECRET")), nil
+		}
+		// Only keys we signed with are trusted, and only with their own algorithm.
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.findKey(kid)
+		if !ok || key.method.Alg() != token.Method.Alg() {
 			return nil, errors.New("token is invalid")
 		}
-		return []byte(os.Getenv("SECRET")), nil
+		return key.privateKey.Public(), nil
 	})
 	if err != nil {
 		return nil, err
@@ -50,6 +111,54 @@ func (*jwtUtils) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 	return claims, nil
 }
 
-func NewJwtUtils() JwtUtils {
-	return &jwtUtils{}
+func (j *jwtUtils) findKey(kid string) (jwtKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	for _, key := range j.keys {
+		if key.kid == kid {
+			return key, true
+		}
+	}
+	return jwtKey{}, false
+}
+
+// SetSigningKeys replaces the keys tokens are signed and verified with. It is a no-op with HS256.
+func (j *jwtUtils) SetSigningKeys(keys []model.SigningKey) error {
+	if j.algorithm == JwtAlgorithmHS256 {
+		return nil
+	}
+	parsed := make([]jwtKey, 0, len(keys))
+	for _, key := range keys {
+		privateKey, err := parsePrivateKey(key, j.keyEncryptionKey)
+		if err != nil {
+			log.Println("Jwt service, set signing keys err :", err)
+			return err
+		}
+		parsed = append(parsed, jwtKey{
+			kid:         key.Kid,
+			method:      jwt.GetSigningMethod(key.Algorithm),
+			privateKey:  privateKey,
+			activatesAt: key.ActivatesAt,
+		})
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.keys = parsed
+	return nil
+}
+
+// GetJwks returns the public keys tokens may be verified with, including the ones not signing yet so that
+// verifiers can cache them ahead of time.
+func (j *jwtUtils) GetJwks() response.JwksResponse {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	jwks := response.JwksResponse{Keys: []response.Jwk{}}
+	for _, key := range j.keys {
+		jwks.Keys = append(jwks.Keys, toJwk(key.kid, key.method.Alg(), key.privateKey.Public()))
+	}
+	return jwks
+}
+
+func NewJwtUtils(algorithm string, keyEncryptionKey []byte) JwtUtils {
+	return &jwtUtils{algorithm: algorithm, keyEncryptionKey: keyEncryptionKey}
 }
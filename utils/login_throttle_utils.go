@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLoginMaxAccountFailures = 5
+	defaultLoginMaxIpFailures      = 50
+	defaultLoginLockoutDuration    = 15 * time.Minute
+	maxLoginDelay                  = 30 * time.Second
+)
+
+// LoginThrottlePolicy decides how long logins are blocked after a number of consecutive failures.
+type LoginThrottlePolicy struct {
+	MaxFailures int
+	Lockout     time.Duration
+}
+
+// Delay is 0 for the first half of MaxFailures, then doubles from one second with every failure, and becomes the
+// lockout once MaxFailures is reached.
+func (p LoginThrottlePolicy) Delay(failures int) time.Duration {
+	if failures >= p.MaxFailures {
+		return p.Lockout
+	}
+	free := p.MaxFailures / 2
+	if failures <= free {
+		return 0
+	}
+	shift := failures - free - 1
+	if shift >= 5 {
+		return min(maxLoginDelay, p.Lockout)
+	}
+	return min(time.Second<<shift, maxLoginDelay, p.Lockout)
+}
+
+// GetAccountLoginThrottlePolicy reads LOGIN_MAX_FAILURES and LOGIN_LOCKOUT_MINUTES.
+func GetAccountLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		MaxFailures: getPositiveIntEnv("LOGIN_MAX_FAILURES", defaultLoginMaxAccountFailures),
+		Lockout:     getLoginLockoutDuration(),
+	}
+}
+
+// GetIpLoginThrottlePolicy reads LOGIN_MAX_IP_FAILURES and LOGIN_LOCKOUT_MINUTES.
+func GetIpLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		MaxFailures: getPositiveIntEnv("LOGIN_MAX_IP_FAILURES", defaultLoginMaxIpFailures),
+		Lockout:     getLoginLockoutDuration(),
+	}
+}
+
+func getLoginLockoutDuration() time.Duration {
+	minutes := getPositiveIntEnv("LOGIN_LOCKOUT_MINUTES", int(defaultLoginLockoutDuration/time.Minute))
+	return time.Duration(minutes) * time.Minute
+}
+
+func getPositiveIntEnv(name string, defaultValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		log.Fatal("Invalid ", name, ": ", value)
+	}
+	return number
+}
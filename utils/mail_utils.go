@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+type MailSender interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
+
+type smtpMailSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func (s *smtpMailSender) Send(_ context.Context, to string, subject string, body string) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	msg := strings.Join([]string{
+		"From: " + s.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+	err := smtp.SendMail(s.addr, auth, s.from, []string{to}, []byte(msg))
+	if err != nil {
+		log.Println("Mail sender, send mail err :", err)
+		return err
+	}
+	return nil
+}
+
+// logMailSender only logs that a mail was sent, for development without a mail server. The body is left out as it
+// holds secrets such as password reset tokens.
+type logMailSender struct{}
+
+func (*logMailSender) Send(_ context.Context, to string, subject string, _ string) error {
+	log.Printf("Mail sender, mail to %s not sent, subject: %s", to, subject)
+	return nil
+}
+
+// NewMailSender sends through the SMTP server at SMTP_HOST:SMTP_PORT, authenticating only when SMTP_USERNAME is
+// set so that a local stand-in such as MailHog works out of the box. Mails are only dropped when MAIL_LOG_ONLY is
+// true, without SMTP_HOST the server refuses to start.
+func NewMailSender() MailSender {
+	if os.Getenv("MAIL_LOG_ONLY") == "true" {
+		log.Println("Mail sender, MAIL_LOG_ONLY is set, mails are not sent")
+		return &logMailSender{}
+	}
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Fatal("SMTP_HOST is required unless MAIL_LOG_ONLY is true")
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "25"
+	}
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+	return &smtpMailSender{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     from,
+	}
+}
@@ -0,0 +1,466 @@
+package utils
+
+import (
+	"SchoolManagement/dto/response"
+	"SchoolManagement/model"
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOidcScopes = "openid email profile"
+	oidcHttpTimeout   = 10 * time.Second
+	// oidcKeyRefetchInterval limits how often an ID token with an unknown kid makes the provider keys be fetched
+	// again, so that forged tokens cannot be used to hammer the provider.
+	oidcKeyRefetchInterval = time.Minute
+	// oidcClockLeeway tolerates clocks of the provider and the server being slightly apart.
+	oidcClockLeeway     = time.Minute
+	oidcMaxResponseSize = 1 << 20
+)
+
+// OidcConfig configures single sign-on through an OpenID Connect provider. It is disabled when Issuer is empty.
+type OidcConfig struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+	// ProvisionDomains are the email domains whose unknown users are created as students on their first login.
+	ProvisionDomains []string
+	// TrustUnverifiedEmail matches users by email even when the provider does not claim the email as verified.
+	TrustUnverifiedEmail bool
+}
+
+func (c OidcConfig) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// CanProvision reports whether a user with the email may be created on their first login.
+func (c OidcConfig) CanProvision(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, provisionDomain := range c.ProvisionDomains {
+		if domain == provisionDomain {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOidcConfig reads OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL, OIDC_SCOPES,
+// OIDC_PROVISION_DOMAINS and OIDC_TRUST_UNVERIFIED_EMAIL. Without OIDC_ISSUER single sign-on is disabled.
+func GetOidcConfig() OidcConfig {
+	issuer := strings.TrimSuffix(os.Getenv("OIDC_ISSUER"), "/")
+	if issuer == "" {
+		return OidcConfig{}
+	}
+	config := OidcConfig{
+		Issuer:               issuer,
+		ClientId:             os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:         os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectUrl:          os.Getenv("OIDC_REDIRECT_URL"),
+		TrustUnverifiedEmail: os.Getenv("OIDC_TRUST_UNVERIFIED_EMAIL") == "true",
+	}
+	if config.ClientId == "" {
+		log.Fatal("OIDC_CLIENT_ID is required when OIDC_ISSUER is set")
+	}
+	if config.RedirectUrl == "" {
+		log.Fatal("OIDC_REDIRECT_URL is required when OIDC_ISSUER is set")
+	}
+	scopes := os.Getenv("OIDC_SCOPES")
+	if scopes == "" {
+		scopes = defaultOidcScopes
+	}
+	config.Scopes = strings.Fields(scopes)
+	if !containsString(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	for _, domain := range strings.Split(os.Getenv("OIDC_PROVISION_DOMAINS"), ",") {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			config.ProvisionDomains = append(config.ProvisionDomains, domain)
+		}
+	}
+	return config
+}
+
+// PkceChallenge derives the S256 code challenge of RFC 7636 from the code verifier.
+func PkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type OidcClient interface {
+	AuthorizationUrl(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	ExchangeCode(ctx context.Context, code string, codeVerifier string) (string, error)
+	VerifyIdToken(ctx context.Context, rawIdToken string, nonce string) (model.OidcClaims, error)
+}
+
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type oidcKey struct {
+	kid       string
+	alg       string
+	publicKey crypto.PublicKey
+}
+
+type oidcClient struct {
+	config     OidcConfig
+	httpClient *http.Client
+	mu         sync.Mutex
+	metadata   *oidcMetadata
+	keys       []oidcKey
+	// keysFetchedAt is zero until the keys were fetched once.
+	keysFetchedAt time.Time
+}
+
+// AuthorizationUrl is where the browser is sent to log in at the provider. The provider sends it back to the
+// redirect URL with the state and a code that only the holder of the code verifier can exchange.
+func (o *oidcClient) AuthorizationUrl(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	metadata, err := o.getMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {o.config.ClientId},
+		"redirect_uri":          {o.config.RedirectUrl},
+		"scope":                 {strings.Join(o.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {PkceChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// ExchangeCode redeems the authorization code at the token endpoint and returns the raw ID token.
+func (o *oidcClient) ExchangeCode(ctx context.Context, code string, codeVerifier string) (string, error) {
+	metadata, err := o.getMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.config.RedirectUrl},
+		"code_verifier": {codeVerifier},
+	}
+	if o.config.ClientSecret == "" {
+		form.Set("client_id", o.config.ClientId)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(o.config.ClientId), url.QueryEscape(o.config.ClientSecret))
+	}
+	res, err := o.httpClient.Do(req)
+	if err != nil {
+		log.Println("Oidc client, exchange code err :", err)
+		return "", err
+	}
+	defer res.Body.Close()
+	var tokenResponse struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(io.LimitReader(res.Body, oidcMaxResponseSize)).Decode(&tokenResponse)
+	if err != nil {
+		log.Println("Oidc client, exchange code err :", err)
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint answered %d: %s %s", res.StatusCode, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if tokenResponse.IdToken == "" {
+		return "", errors.New("token endpoint returned no id_token")
+	}
+	return tokenResponse.IdToken, nil
+}
+
+// VerifyIdToken checks the signature of the ID token against the keys the provider publishes, that it was issued
+// by the provider for this client and that it belongs to the login that sent the nonce.
+func (o *oidcClient) VerifyIdToken(ctx context.Context, rawIdToken string, nonce string) (model.OidcClaims, error) {
+	metadata, err := o.getMetadata(ctx)
+	if err != nil {
+		return model.OidcClaims{}, err
+	}
+	parser := jwt.Parser{
+		ValidMethods:         []string{"RS256", "ES256", "EdDSA"},
+		SkipClaimsValidation: true,
+	}
+	token, err := parser.Parse(rawIdToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, e := o.findKey(ctx, kid)
+		if e != nil {
+			return nil, e
+		}
+		if key.alg != "" && key.alg != token.Method.Alg() {
+			return nil, errors.New("key " + kid + " is not meant for " + token.Method.Alg())
+		}
+		return key.publicKey, nil
+	})
+	if err != nil {
+		return model.OidcClaims{}, err
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	now := time.Now()
+	expiresAt, ok := claims["exp"].(float64)
+	if !ok || now.Add(-oidcClockLeeway).Unix() > int64(expiresAt) {
+		return model.OidcClaims{}, errors.New("id token is expired")
+	}
+	if notBefore, ok := claims["nbf"].(float64); ok && now.Add(oidcClockLeeway).Unix() < int64(notBefore) {
+		return model.OidcClaims{}, errors.New("id token is not valid yet")
+	}
+	// Tokens carry the issuer exactly as the provider spells it, which may include a trailing slash.
+	if issuer, _ := claims["iss"].(string); issuer != metadata.Issuer {
+		return model.OidcClaims{}, errors.New("id token was issued by " + issuer)
+	}
+	audiences := claimStrings(claims["aud"])
+	if !containsString(audiences, o.config.ClientId) {
+		return model.OidcClaims{}, errors.New("id token is not meant for this client")
+	}
+	if authorizedParty, ok := claims["azp"].(string); ok && authorizedParty != o.config.ClientId {
+		return model.OidcClaims{}, errors.New("id token was authorized for " + authorizedParty)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return model.OidcClaims{}, errors.New("id token nonce does not match")
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return model.OidcClaims{}, errors.New("id token has no subject")
+	}
+	oidcClaims := model.OidcClaims{Issuer: o.config.Issuer, Subject: subject}
+	oidcClaims.Email, _ = claims["email"].(string)
+	oidcClaims.Name, _ = claims["name"].(string)
+	oidcClaims.Birthdate, _ = claims["birthdate"].(string)
+	// Some providers send the flag as a string.
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		oidcClaims.EmailVerified = verified
+	case string:
+		oidcClaims.EmailVerified = verified == "true"
+	}
+	return oidcClaims, nil
+}
+
+// getMetadata fetches the discovery document of the provider once. A failed fetch is tried again on the next call.
+func (o *oidcClient) getMetadata(ctx context.Context) (oidcMetadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.metadata != nil {
+		return *o.metadata, nil
+	}
+	var metadata oidcMetadata
+	err := o.getJson(ctx, o.config.Issuer+"/.well-known/openid-configuration", &metadata)
+	if err != nil {
+		return oidcMetadata{}, err
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != o.config.Issuer {
+		return oidcMetadata{}, errors.New("discovery document belongs to issuer " + metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksUri == "" {
+		return oidcMetadata{}, errors.New("discovery document misses an endpoint")
+	}
+	o.metadata = &metadata
+	return metadata, nil
+}
+
+// findKey looks the key up in the cached provider keys, fetching them again when the kid is unknown because the
+// provider may have rotated its keys. A token without kid is accepted when the provider has a single key.
+func (o *oidcClient) findKey(ctx context.Context, kid string) (oidcKey, error) {
+	metadata, err := o.getMetadata(ctx)
+	if err != nil {
+		return oidcKey{}, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	key, found := o.lookupKey(kid)
+	if found {
+		return key, nil
+	}
+	if !o.keysFetchedAt.IsZero() && time.Since(o.keysFetchedAt) < oidcKeyRefetchInterval {
+		return oidcKey{}, errors.New("unknown key " + kid)
+	}
+	var jwks response.JwksResponse
+	err = o.getJson(ctx, metadata.JwksUri, &jwks)
+	if err != nil {
+		return oidcKey{}, err
+	}
+	o.keysFetchedAt = time.Now()
+	o.keys = nil
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		publicKey, e := parseJwk(jwk)
+		if e != nil {
+			log.Println("Oidc client, skipping provider key ", jwk.Kid, " err :", e)
+			continue
+		}
+		o.keys = append(o.keys, oidcKey{kid: jwk.Kid, alg: jwk.Alg, publicKey: publicKey})
+	}
+	key, found = o.lookupKey(kid)
+	if !found {
+		return oidcKey{}, errors.New("unknown key " + kid)
+	}
+	return key, nil
+}
+
+func (o *oidcClient) lookupKey(kid string) (oidcKey, bool) {
+	if kid == "" {
+		if len(o.keys) == 1 {
+			return o.keys[0], true
+		}
+		return oidcKey{}, false
+	}
+	for _, key := range o.keys {
+		if key.kid == kid {
+			return key, true
+		}
+	}
+	return oidcKey{}, false
+}
+
+func (o *oidcClient) getJson(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := o.httpClient.Do(req)
+	if err != nil {
+		log.Println("Oidc client, get ", url, " err :", err)
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %d", url, res.StatusCode)
+	}
+	err = json.NewDecoder(io.LimitReader(res.Body, oidcMaxResponseSize)).Decode(v)
+	if err != nil {
+		log.Println("Oidc client, get ", url, " err :", err)
+		return err
+	}
+	return nil
+}
+
+// parseJwk reads the RSA, P-256 and Ed25519 public keys ID tokens may be signed with.
+func parseJwk(jwk response.Jwk) (crypto.PublicKey, error) {
+	switch {
+	case jwk.Kty == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case jwk.Kty == "EC" && jwk.Crv == "P-256":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 key")
+		}
+		// ecdh rejects points that are not on the curve.
+		_, err = ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, errors.New("unsupported key type " + jwk.Kty + " " + jwk.Crv)
+}
+
+// claimStrings reads a claim that may be a single string or an array of strings, such as aud.
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewOidcClient talks to the provider at config.Issuer, which may be a plain http URL so that a local mock
+// provider can stand in during development. The discovery document is fetched on the first login.
+func NewOidcClient(config OidcConfig) OidcClient {
+	return &oidcClient{
+		config:     config,
+		httpClient: &http.Client{Timeout: oidcHttpTimeout},
+	}
+}
@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"SchoolManagement/dto/response"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	mockIdpClientId     = "school"
+	mockIdpClientSecret = "secret"
+	mockIdpRedirectUrl  = "http://localhost:8080/auth/oidc/callback"
+)
+
+// mockIdp is an OpenID Connect provider that logs in whoever its authorize method is called for. It checks the
+// client, the redirect URI and the PKCE verifier like a real provider does.
+type mockIdp struct {
+	t      *testing.T
+	server *httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+	mu     sync.Mutex
+	// codes maps each issued code to the ID token it is exchanged for and the PKCE challenge of its login.
+	codes map[string]mockIdpCode
+}
+
+type mockIdpCode struct {
+	idToken       string
+	codeChallenge string
+}
+
+func newMockIdp(t *testing.T) *mockIdp {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdp{t: t, rsaKey: rsaKey, ecKey: ecKey, codes: make(map[string]mockIdpCode)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (m *mockIdp) issuer() string {
+	return m.server.URL
+}
+
+func (m *mockIdp) config() OidcConfig {
+	return OidcConfig{
+		Issuer:       m.issuer(),
+		ClientId:     mockIdpClientId,
+		ClientSecret: mockIdpClientSecret,
+		RedirectUrl:  mockIdpRedirectUrl,
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+func (m *mockIdp) discovery(w http.ResponseWriter, _ *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 m.issuer(),
+		"authorization_endpoint": m.issuer() + "/authorize",
+		"token_endpoint":         m.issuer() + "/token",
+		"jwks_uri":               m.issuer() + "/jwks",
+	})
+}
+
+func (m *mockIdp) jwks(w http.ResponseWriter, _ *http.Request) {
+	rsaJwk := response.Jwk{
+		Kty: "RSA", Kid: "rsa", Use: "sig", Alg: "RS256",
+		N: base64.RawURLEncoding.EncodeToString(m.rsaKey.N.Bytes()),
+		E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.rsaKey.E)).Bytes()),
+	}
+	ecJwk := response.Jwk{
+		Kty: "EC", Kid: "ec", Use: "sig", Alg: "ES256", Crv: "P-256",
+		X: base64.RawURLEncoding.EncodeToString(m.ecKey.X.FillBytes(make([]byte, 32))),
+		Y: base64.RawURLEncoding.EncodeToString(m.ecKey.Y.FillBytes(make([]byte, 32))),
+	}
+	_ = json.NewEncoder(w).Encode(response.JwksResponse{Keys: []response.Jwk{rsaJwk, ecJwk}})
+}
+
+func (m *mockIdp) token(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != mockIdpClientId || clientSecret != mockIdpClientSecret {
+		m.tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != mockIdpRedirectUrl {
+		m.tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	m.mu.Lock()
+	code, found := m.codes[r.PostFormValue("code")]
+	delete(m.codes, r.PostFormValue("code"))
+	m.mu.Unlock()
+	if !found || PkceChallenge(r.PostFormValue("code_verifier")) != code.codeChallenge {
+		m.tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": code.idToken, "token_type": "Bearer"})
+}
+
+func (m *mockIdp) tokenError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// authorize plays the user logging in at the authorization URL and returns the code the browser is redirected
+// back with. edit may change the claims or the signing method of the ID token.
+func (m *mockIdp) authorize(authorizationUrl string, edit func(claims jwt.MapClaims) jwt.SigningMethod) string {
+	m.t.Helper()
+	parsed, err := url.Parse(authorizationUrl)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	query := parsed.Query()
+	if parsed.Path != "/authorize" || query.Get("client_id") != mockIdpClientId || query.Get("redirect_uri") != mockIdpRedirectUrl ||
+		query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" ||
+		query.Get("state") == "" || !strings.Contains(query.Get("scope"), "openid") {
+		m.t.Fatalf("unexpected authorization url %s", authorizationUrl)
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.issuer(),
+		"sub":            "user-1",
+		"aud":            mockIdpClientId,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          query.Get("nonce"),
+		"email":          "Ada@Student.Example.edu",
+		"email_verified": true,
+		"name":           "Ada",
+	}
+	method := jwt.SigningMethod(jwt.SigningMethodRS256)
+	if edit != nil {
+		method = edit(claims)
+	}
+	idToken := m.sign(method, claims)
+	code, err := GenerateToken()
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	m.mu.Lock()
+	m.codes[code] = mockIdpCode{idToken: idToken, codeChallenge: query.Get("code_challenge")}
+	m.mu.Unlock()
+	return code
+}
+
+func (m *mockIdp) sign(method jwt.SigningMethod, claims jwt.MapClaims) string {
+	m.t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	var key interface{} = m.rsaKey
+	token.Header["kid"] = "rsa"
+	if method == jwt.SigningMethodES256 {
+		key = m.ecKey
+		token.Header["kid"] = "ec"
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return signed
+}
+
+func TestOidcClientLogin(t *testing.T) {
+	idp := newMockIdp(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		// edit changes the ID token the provider issues.
+		edit func(claims jwt.MapClaims) jwt.SigningMethod
+		// verifier replaces the code verifier of the login when set.
+		verifier    string
+		wantErr     string
+		wantSubject string
+	}{
+		{name: "RS256 token", wantSubject: "user-1"},
+		{
+			name:        "ES256 token",
+			edit:        func(jwt.MapClaims) jwt.SigningMethod { return jwt.SigningMethodES256 },
+			wantSubject: "user-1",
+		},
+		{name: "wrong code verifier", verifier: "not-the-verifier", wantErr: "invalid_grant"},
+		{
+			name: "other nonce",
+			edit: func(claims jwt.MapClaims) jwt.SigningMethod {
+				claims["nonce"] = "replayed"
+				return jwt.SigningMethodRS256
+			},
+			wantErr: "nonce",
+		},
+		{
+			name: "other audience",
+			edit: func(claims jwt.MapClaims) jwt.SigningMethod {
+				claims["aud"] = "other-client"
+				return jwt.SigningMethodRS256
+			},
+			wantErr: "not meant for this client",
+		},
+		{
+			name: "other issuer",
+			edit: func(claims jwt.MapClaims) jwt.SigningMethod {
+				claims["iss"] = "https://evil.example"
+				return jwt.SigningMethodRS256
+			},
+			wantErr: "issued by",
+		},
+		{
+			name: "expired token",
+			edit: func(claims jwt.MapClaims) jwt.SigningMethod {
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return jwt.SigningMethodRS256
+			},
+			wantErr: "expired",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := NewOidcClient(idp.config())
+			nonce, codeVerifier := "nonce-"+test.name, "verifier-"+test.name
+			authorizationUrl, err := client.AuthorizationUrl(ctx, "state", nonce, codeVerifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code := idp.authorize(authorizationUrl, test.edit)
+			if test.verifier != "" {
+				codeVerifier = test.verifier
+			}
+			rawIdToken, err := client.ExchangeCode(ctx, code, codeVerifier)
+			if err == nil {
+				oidcClaims, e := client.VerifyIdToken(ctx, rawIdToken, nonce)
+				err = e
+				if err == nil && (oidcClaims.Subject != test.wantSubject || oidcClaims.Issuer != idp.issuer() ||
+					oidcClaims.Email != "Ada@Student.Example.edu" || !oidcClaims.EmailVerified) {
+					t.Fatalf("unexpected claims %+v", oidcClaims)
+				}
+			}
+			if test.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("token signed with an unknown key", func(t *testing.T) {
+		client := NewOidcClient(idp.config())
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss": idp.issuer(), "sub": "user-1", "aud": mockIdpClientId, "nonce": "n", "exp": time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = "rsa"
+		forged, err := token.SignedString(otherKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.VerifyIdToken(context.Background(), forged, "n")
+		if err == nil {
+			t.Fatal("expected a token signed with another key to be rejected")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPasswordResetDuration    = 30 * time.Minute
+	defaultPasswordResetMaxPerEmail = 3
+	defaultPasswordResetMaxPerIp    = 20
+	passwordResetLimitWindow        = time.Hour
+)
+
+// PasswordResetLimit caps the reset requests per email and per IP address within Window.
+type PasswordResetLimit struct {
+	MaxPerEmail int
+	MaxPerIp    int
+	Window      time.Duration
+}
+
+// GetPasswordResetDuration reads PASSWORD_RESET_MINUTES, how long a password reset token can be used.
+func GetPasswordResetDuration() time.Duration {
+	value := os.Getenv("PASSWORD_RESET_MINUTES")
+	if value == "" {
+		return defaultPasswordResetDuration
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes <= 0 {
+		log.Fatal("Invalid PASSWORD_RESET_MINUTES: ", value)
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetPasswordResetLimit reads PASSWORD_RESET_MAX_PER_EMAIL and PASSWORD_RESET_MAX_PER_IP, how many password reset
+// requests are accepted per hour.
+func GetPasswordResetLimit() PasswordResetLimit {
+	return PasswordResetLimit{
+		MaxPerEmail: getPositiveIntEnv("PASSWORD_RESET_MAX_PER_EMAIL", defaultPasswordResetMaxPerEmail),
+		MaxPerIp:    getPositiveIntEnv("PASSWORD_RESET_MAX_PER_IP", defaultPasswordResetMaxPerIp),
+		Window:      passwordResetLimitWindow,
+	}
+}
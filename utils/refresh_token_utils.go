@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultRefreshTokenHours = 7 * 24
+
+// GetRefreshTokenDuration reads REFRESH_TOKEN_HOURS, how long a refresh token stays valid. It defaults to 7 days.
+func GetRefreshTokenDuration() time.Duration {
+	value := os.Getenv("REFRESH_TOKEN_HOURS")
+	if value == "" {
+		return defaultRefreshTokenHours * time.Hour
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours <= 0 {
+		log.Fatal("Invalid REFRESH_TOKEN_HOURS: ", value)
+	}
+	return time.Duration(hours) * time.Hour
+}
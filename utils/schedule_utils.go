@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"SchoolManagement/model"
+	"errors"
+	"sort"
+	"time"
+	_ "time/tzdata"
+)
+
+const (
+	DateLayout = "2006-01-02"
+	TimeLayout = "15:04"
+)
+
+// ValidateCourseSchedule checks that every field of a recurring pattern can be parsed and is consistent.
+func ValidateCourseSchedule(schedule model.CourseSchedule) error {
+	if schedule.DayOfWeek < 1 || schedule.DayOfWeek > 7 {
+		return errors.New("day of week must be between 1 (Monday) and 7 (Sunday)")
+	}
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return errors.New("unknown timezone " + schedule.Timezone)
+	}
+	startTime, err := time.Parse(TimeLayout, schedule.StartTime)
+	if err != nil {
+		return errors.New("start time must be in HH:MM format")
+	}
+	endTime, err := time.Parse(TimeLayout, schedule.EndTime)
+	if err != nil {
+		return errors.New("end time must be in HH:MM format")
+	}
+	if !startTime.Before(endTime) {
+		return errors.New("start time must be before end time")
+	}
+	startDate, err := time.Parse(DateLayout, schedule.StartDate)
+	if err != nil {
+		return errors.New("start date must be in YYYY-MM-DD format")
+	}
+	endDate, err := time.Parse(DateLayout, schedule.EndDate)
+	if err != nil {
+		return errors.New("end date must be in YYYY-MM-DD format")
+	}
+	if endDate.Before(startDate) {
+		return errors.New("start date must not be after end date")
+	}
+	for _, exceptionDate := range schedule.ExceptionDates {
+		if _, err = time.Parse(DateLayout, exceptionDate); err != nil {
+			return errors.New("exception dates must be in YYYY-MM-DD format")
+		}
+	}
+	return nil
+}
+
+// ExpandCourseSchedule returns the concrete sessions of a recurring pattern that intersect [from, to).
+func ExpandCourseSchedule(schedule model.CourseSchedule, from time.Time, to time.Time) ([]model.CourseSession, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	startDate, err := time.ParseInLocation(DateLayout, schedule.StartDate, location)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := time.ParseInLocation(DateLayout, schedule.EndDate, location)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := time.Parse(TimeLayout, schedule.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := time.Parse(TimeLayout, schedule.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	exceptions := make(map[string]bool)
+	for _, exceptionDate := range schedule.ExceptionDates {
+		exceptions[exceptionDate] = true
+	}
+
+	// Sessions never span more than a day, so dates outside [from - 1 day, to + 1 day] cannot intersect.
+	first := from.In(location).AddDate(0, 0, -1)
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, location)
+	if first.Before(startDate) {
+		first = startDate
+	}
+	last := to.In(location).AddDate(0, 0, 1)
+	if last.After(endDate) {
+		last = endDate
+	}
+	weekday := time.Weekday(schedule.DayOfWeek % 7)
+	first = first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7)
+
+	var sessions []model.CourseSession
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 7) {
+		if exceptions[day.Format(DateLayout)] {
+			continue
+		}
+		sessionStart := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, location)
+		sessionEnd := time.Date(day.Year(), day.Month(), day.Day(), endTime.Hour(), endTime.Minute(), 0, 0, location)
+		if !sessionStart.Before(to) || !sessionEnd.After(from) {
+			continue
+		}
+		sessions = append(sessions, model.CourseSession{
+			ScheduleId: schedule.Id,
+			CourseId:   schedule.CourseId,
+			Room:       schedule.Room,
+			StartTime:  sessionStart.Unix(),
+			EndTime:    sessionEnd.Unix(),
+		})
+	}
+	return sessions, nil
+}
+
+// ScheduleDateRange returns the instants bounding every session of the pattern.
+func ScheduleDateRange(schedule model.CourseSchedule) (time.Time, time.Time, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	startDate, err := time.ParseInLocation(DateLayout, schedule.StartDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endDate, err := time.ParseInLocation(DateLayout, schedule.EndDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return startDate, endDate.AddDate(0, 0, 1), nil
+}
+
+// FindScheduleOverlap returns the first session of other that intersects a session of schedule.
+func FindScheduleOverlap(schedule model.CourseSchedule, other model.CourseSchedule) (model.CourseSession, bool, error) {
+	from, to, err := ScheduleDateRange(schedule)
+	if err != nil {
+		return model.CourseSession{}, false, err
+	}
+	otherFrom, otherTo, err := ScheduleDateRange(other)
+	if err != nil {
+		return model.CourseSession{}, false, err
+	}
+	if otherFrom.After(from) {
+		from = otherFrom
+	}
+	if otherTo.Before(to) {
+		to = otherTo
+	}
+	if !from.Before(to) {
+		return model.CourseSession{}, false, nil
+	}
+	sessions, err := ExpandCourseSchedule(schedule, from, to)
+	if err != nil {
+		return model.CourseSession{}, false, err
+	}
+	otherSessions, err := ExpandCourseSchedule(other, from, to)
+	if err != nil {
+		return model.CourseSession{}, false, err
+	}
+	session, ok := FindSessionOverlap(sessions, otherSessions)
+	return session, ok, nil
+}
+
+// FindSessionOverlap returns the first session of others that intersects a session in sessions.
+func FindSessionOverlap(sessions []model.CourseSession, others []model.CourseSession) (model.CourseSession, bool) {
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartTime < sessions[j].StartTime })
+	sort.Slice(others, func(i, j int) bool { return others[i].StartTime < others[j].StartTime })
+	i, j := 0, 0
+	for i < len(sessions) && j < len(others) {
+		if sessions[i].StartTime < others[j].EndTime && others[j].StartTime < sessions[i].EndTime {
+			return others[j], true
+		}
+		if sessions[i].EndTime <= others[j].EndTime {
+			i++
+		} else {
+			j++
+		}
+	}
+	return model.CourseSession{}, false
+}
+
+// WeekStart returns the Monday of the week containing date.
+func WeekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// ScheduleDateInWeek returns the local date on which the pattern meets in the week starting on monday, if it meets at all.
+func ScheduleDateInWeek(schedule model.CourseSchedule, monday time.Time) (string, bool) {
+	date := monday.AddDate(0, 0, schedule.DayOfWeek-1).Format(DateLayout)
+	if date < schedule.StartDate || date > schedule.EndDate {
+		return "", false
+	}
+	for _, exceptionDate := range schedule.ExceptionDates {
+		if exceptionDate == date {
+			return "", false
+		}
+	}
+	return date, true
+}
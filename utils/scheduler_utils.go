@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// GetCourseStatusSchedulerInterval reads COURSE_STATUS_SCHEDULER_INTERVAL as a Go duration such as "30m".
+// It defaults to one hour, and 0 turns the scheduler off.
+func GetCourseStatusSchedulerInterval() time.Duration {
+	value := os.Getenv("COURSE_STATUS_SCHEDULER_INTERVAL")
+	if value == "" {
+		return time.Hour
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Fatal("Invalid COURSE_STATUS_SCHEDULER_INTERVAL: ", value)
+	}
+	return interval
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TOTP parameters of RFC 6238 as understood by every common authenticator app.
+const (
+	totpPeriod       = 30
+	totpDigits       = 6
+	totpSecretLength = 20
+	// totpSkew is how many periods a code may be off, to allow for clock drift.
+	totpSkew           = 1
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func GenerateTotpSecret() (string, error) {
+	secret := make([]byte, totpSecretLength)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// GetTotpIssuer reads TOTP_ISSUER, the name authenticator apps show next to the account.
+func GetTotpIssuer() string {
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		return "SchoolManagement"
+	}
+	return issuer
+}
+
+// TotpProvisioningUri returns the otpauth URI that authenticator apps import, usually by scanning it as a QR code.
+func TotpProvisioningUri(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	// Some authenticators show a "+" literally, a space in the issuer has to be escaped as %20.
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func TotpStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+func TotpCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod), nil
+}
+
+// VerifyTotp returns the time step the code belongs to when it is valid around now.
+func VerifyTotp(secret string, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	current := TotpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := TotpCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// IsTotpCode tells a TOTP code from a recovery code.
+func IsTotpCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateRecoveryCodes returns single use codes formatted as two groups of five characters.
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, recoveryCodeLength)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, err
+		}
+		code := totpEncoding.EncodeToString(raw)[:recoveryCodeLength]
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode makes a recovery code comparable regardless of case, dashes and spaces.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
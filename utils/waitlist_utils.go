@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetWaitlistOfferDuration reads WAITLIST_OFFER_HOURS. When it is unset or 0 a freed seat goes straight to the
+// first waitlisted student, otherwise that student is offered the seat and has to accept it within the given hours.
+func GetWaitlistOfferDuration() time.Duration {
+	value := os.Getenv("WAITLIST_OFFER_HOURS")
+	if value == "" {
+		return 0
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 0 {
+		log.Fatal("Invalid WAITLIST_OFFER_HOURS: ", value)
+	}
+	return time.Duration(hours) * time.Hour
+}